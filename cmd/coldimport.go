@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/conflux-chain/conflux-infura/store/mysql"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/sirupsen/logrus"
+)
+
+var coldImportCmd = &cobra.Command{
+	Use:   "cold-import",
+	Short: "Bulk load blocks from a local chaindata LevelDB into the MySQL store",
+	Long: "Bulk load headers, bodies, receipts and logs directly out of a locally-mounted " +
+		"geth/Scroll `chaindata` LevelDB into the MySQL store, bypassing JSON-RPC entirely. " +
+		"Intended for bootstrapping a new gateway, since pulling ~100M blocks over RPC is infeasible.",
+	Run: coldImportRun,
+}
+
+func init() {
+	coldImportCmd.Flags().String("chaindata", "", "path to the geth/Scroll chaindata LevelDB directory")
+	coldImportCmd.Flags().Uint64("starting-block", 0, "first block number (inclusive) to import")
+	coldImportCmd.Flags().Uint64("ending-block", 0, "last block number (inclusive) to import")
+	coldImportCmd.Flags().Bool("sync-all", false, "import from starting-block up to the chaindata head, ignoring ending-block")
+	coldImportCmd.Flags().Uint64("chain-id", 0, "chain ID of the chaindata being imported, used to derive receipt/log linkage fields")
+
+	viper.BindPFlag("coldImport.chainDataDir", coldImportCmd.Flags().Lookup("chaindata"))
+	viper.BindPFlag("coldImport.startingBlock", coldImportCmd.Flags().Lookup("starting-block"))
+	viper.BindPFlag("coldImport.endingBlock", coldImportCmd.Flags().Lookup("ending-block"))
+	viper.BindPFlag("coldImport.syncAll", coldImportCmd.Flags().Lookup("sync-all"))
+	viper.BindPFlag("coldImport.chainId", coldImportCmd.Flags().Lookup("chain-id"))
+
+	rootCmd.AddCommand(coldImportCmd)
+}
+
+func coldImportRun(cmd *cobra.Command, args []string) {
+	dbCfg, ok := mysql.NewConfigFromViper()
+	if !ok {
+		logrus.Fatal("store.mysql must be enabled to run cold-import")
+	}
+
+	// ensure the database and its tables/partitions exist before importing into them
+	dbCfg.MustOpenOrCreate(mysql.StoreOption{})
+
+	importer, err := mysql.NewColdImporter(&dbCfg, mysql.ColdImporterConfig{
+		ChainDataDir:  viper.GetString("coldImport.chainDataDir"),
+		StartingBlock: viper.GetUint64("coldImport.startingBlock"),
+		EndingBlock:   viper.GetUint64("coldImport.endingBlock"),
+		SyncAll:       viper.GetBool("coldImport.syncAll"),
+		ChainID:       viper.GetUint64("coldImport.chainId"),
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create cold importer")
+	}
+
+	if err := importer.Run(); err != nil {
+		logrus.WithError(err).Fatal("Cold import failed")
+	}
+}
@@ -0,0 +1,163 @@
+package node
+
+import (
+	"sync"
+	"time"
+)
+
+// ChaosFault describes the fault currently injected for a single upstream URL.
+type ChaosFault struct {
+	// Latency is extra delay added before Route returns the URL.
+	Latency time.Duration
+	// Blackholed means Route never returns this URL until BlackholeUntil elapses.
+	Blackholed     bool
+	BlackholeUntil time.Time
+}
+
+// ChaosRouter wraps a Router and lets integration tests inject latency, drop
+// connections, force routing to a specific URL, or blackhole a node for a
+// configurable window. Faults are toggled at runtime (e.g. via an admin HTTP
+// endpoint), so existing clientProvider code paths can be exercised against
+// real failure modes without standing up an actual chaotic network.
+type ChaosRouter struct {
+	router Router
+
+	mutex sync.RWMutex
+	// url => injected fault for that upstream node
+	faults map[string]*ChaosFault
+	// group => forced url, bypassing the wrapped router entirely
+	forced map[Group]string
+}
+
+// ChaosFaultConfig declaratively describes a fault to apply to a URL as soon
+// as the ChaosRouter is constructed, so a chaos scenario can be described by
+// config (e.g. a test table) instead of only toggled at runtime via the
+// admin endpoint.
+type ChaosFaultConfig struct {
+	URL string
+	// Latency, if positive, is injected for every Route to URL.
+	Latency time.Duration
+	// BlackholeFor, if positive, blackholes URL starting now for that long.
+	BlackholeFor time.Duration
+}
+
+// NewChaosRouter creates a ChaosRouter wrapping the given Router, optionally
+// seeding initial faults described by configs. Until a fault is injected
+// (via configs or at runtime), Route behaves exactly like the wrapped Router.
+func NewChaosRouter(router Router, configs ...ChaosFaultConfig) *ChaosRouter {
+	cr := &ChaosRouter{
+		router: router,
+		faults: make(map[string]*ChaosFault),
+		forced: make(map[Group]string),
+	}
+
+	for _, cfg := range configs {
+		if cfg.Latency > 0 {
+			cr.InjectLatency(cfg.URL, cfg.Latency)
+		}
+
+		if cfg.BlackholeFor > 0 {
+			cr.Blackhole(cfg.URL, cfg.BlackholeFor)
+		}
+	}
+
+	return cr
+}
+
+// Route implements Router, applying any injected faults before (or instead
+// of) delegating to the wrapped router.
+func (cr *ChaosRouter) Route(group Group, key []byte) string {
+	cr.mutex.RLock()
+	forcedUrl, forced := cr.forced[group]
+	cr.mutex.RUnlock()
+
+	if forced {
+		return cr.applyFault(forcedUrl)
+	}
+
+	url := cr.router.Route(group, key)
+	if len(url) == 0 {
+		return url
+	}
+
+	return cr.applyFault(url)
+}
+
+// applyFault blocks for injected latency and blackholes the URL (returning
+// an empty string so callers observe ErrClientUnavailable) when applicable.
+func (cr *ChaosRouter) applyFault(url string) string {
+	cr.mutex.Lock()
+
+	fault, ok := cr.faults[url]
+	if !ok {
+		cr.mutex.Unlock()
+		return url
+	}
+
+	if fault.Blackholed {
+		if time.Now().Before(fault.BlackholeUntil) {
+			cr.mutex.Unlock()
+			return ""
+		}
+
+		fault.Blackholed = false
+	}
+
+	latency := fault.Latency
+	cr.mutex.Unlock()
+
+	if latency > 0 {
+		time.Sleep(latency)
+	}
+
+	return url
+}
+
+// InjectLatency adds extra delay before Route returns requests bound for url.
+func (cr *ChaosRouter) InjectLatency(url string, latency time.Duration) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	cr.faultFor(url).Latency = latency
+}
+
+// Blackhole makes Route return no URL for url until duration elapses,
+// simulating a dropped/unreachable node.
+func (cr *ChaosRouter) Blackhole(url string, duration time.Duration) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	fault := cr.faultFor(url)
+	fault.Blackholed = true
+	fault.BlackholeUntil = time.Now().Add(duration)
+}
+
+// ForceRoute makes Route always return url for the given group, bypassing
+// the wrapped Router's load-balancing/hashring logic entirely.
+func (cr *ChaosRouter) ForceRoute(group Group, url string) {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	cr.forced[group] = url
+}
+
+// Reset clears all injected faults and forced routes.
+func (cr *ChaosRouter) Reset() {
+	cr.mutex.Lock()
+	defer cr.mutex.Unlock()
+
+	cr.faults = make(map[string]*ChaosFault)
+	cr.forced = make(map[Group]string)
+}
+
+// faultFor returns the fault entry for url, creating it if absent. Callers
+// must hold cr.mutex for writing.
+func (cr *ChaosRouter) faultFor(url string) *ChaosFault {
+	fault, ok := cr.faults[url]
+	if !ok {
+		fault = &ChaosFault{}
+		cr.faults[url] = fault
+	}
+
+	return fault
+}
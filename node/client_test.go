@@ -0,0 +1,122 @@
+package node
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// staticRouter always routes group to the same url, letting tests drive
+// failover purely through a wrapping ChaosRouter's injected faults.
+type staticRouter struct {
+	url string
+}
+
+func (r *staticRouter) Route(group Group, key []byte) string {
+	return r.url
+}
+
+var errDial = errors.New("dial tcp: connection refused")
+
+func TestGetClientReturnsErrClientUnavailableWhenRouteIsBlackholed(t *testing.T) {
+	router := NewChaosRouter(&staticRouter{url: "http://node-a"})
+	router.Blackhole("http://node-a", time.Hour)
+
+	provider := newClientProvider(router, func(url string) (interface{}, error) {
+		return url, nil
+	})
+	provider.registerGroup(Group("eth"))
+
+	_, err := provider.getClient("k1", Group("eth"))
+	if !errors.Is(err, ErrClientUnavailable) {
+		t.Fatalf("getClient() error = %v, want %v", err, ErrClientUnavailable)
+	}
+}
+
+func TestGetClientBubblesUpFactoryConnectionError(t *testing.T) {
+	router := NewChaosRouter(&staticRouter{url: "http://node-a"})
+
+	provider := newClientProvider(router, func(url string) (interface{}, error) {
+		return nil, errDial
+	})
+	provider.registerGroup(Group("eth"))
+
+	_, err := provider.getClient("k1", Group("eth"))
+	if err == nil || !errors.Is(err, errDial) {
+		t.Fatalf("getClient() error = %v, want it to wrap %v", err, errDial)
+	}
+}
+
+func TestGetClientRetriesFactoryAfterATransientError(t *testing.T) {
+	router := NewChaosRouter(&staticRouter{url: "http://node-a"})
+
+	var calls int32
+	provider := newClientProvider(router, func(url string) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return nil, errDial
+		}
+
+		return url, nil
+	})
+	provider.registerGroup(Group("eth"))
+
+	if _, err := provider.getClient("k1", Group("eth")); !errors.Is(err, errDial) {
+		t.Fatalf("first getClient() error = %v, want %v", err, errDial)
+	}
+
+	client, err := provider.getClient("k1", Group("eth"))
+	if err != nil {
+		t.Fatalf("second getClient() error = %v, want nil (factory should be retried, not cached on failure)", err)
+	}
+	if client != "http://node-a" {
+		t.Fatalf("getClient() = %v, want the routed url", client)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("factory called %v times, want exactly 2 (one retry after the transient error)", got)
+	}
+}
+
+func TestGetClientCachesConnectionAcrossFailover(t *testing.T) {
+	router := NewChaosRouter(&staticRouter{url: "http://node-a"})
+
+	var calls int32
+	provider := newClientProvider(router, func(url string) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return url, nil
+	})
+	provider.registerGroup(Group("eth"))
+
+	for i := 0; i < 3; i++ {
+		client, err := provider.getClient("k1", Group("eth"))
+		if err != nil {
+			t.Fatalf("getClient() error = %v", err)
+		}
+		if client != "http://node-a" {
+			t.Fatalf("getClient() = %v, want http://node-a", client)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("factory called %v times, want exactly 1 (subsequent calls should reuse the cached client)", got)
+	}
+
+	// once node-a is blackholed, the provider has no url to dial at all -
+	// the cached client for node-a is irrelevant since Route itself fails.
+	router.Blackhole("http://node-a", time.Hour)
+	if _, err := provider.getClient("k1", Group("eth")); !errors.Is(err, ErrClientUnavailable) {
+		t.Fatalf("getClient() after blackhole error = %v, want %v", err, ErrClientUnavailable)
+	}
+}
+
+func TestGetClientErrorsOnUnregisteredGroup(t *testing.T) {
+	router := NewChaosRouter(&staticRouter{url: "http://node-a"})
+	provider := newClientProvider(router, func(url string) (interface{}, error) {
+		return url, nil
+	})
+
+	if _, err := provider.getClient("k1", Group("eth")); err == nil {
+		t.Fatalf("expected getClient() to error for an unregistered group")
+	}
+}
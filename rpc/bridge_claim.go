@@ -0,0 +1,96 @@
+package rpc
+
+import (
+	"encoding/hex"
+
+	"github.com/conflux-chain/conflux-infura/store/mysql"
+	"github.com/pkg/errors"
+)
+
+// bridgeClaimApiModule is the JSON-RPC namespace exposing withdrawal claim
+// proofs, e.g. `bridge_getClaimProof`.
+const bridgeClaimApiModule = "bridge"
+
+// ClaimProofResult is the wire representation of a withdrawal claim proof.
+type ClaimProofResult struct {
+	Path      []string `json:"path"`
+	Root      string   `json:"root"`
+	LeafIndex uint64   `json:"leafIndex"`
+}
+
+// PendingClaimResult is the wire representation of an unclaimed, indexed
+// withdrawal message.
+type PendingClaimResult struct {
+	MessageHash string `json:"messageHash"`
+	Sender      string `json:"sender"`
+	Recipient   string `json:"recipient"`
+	LeafIndex   uint64 `json:"leafIndex"`
+}
+
+// BridgeClaimAPI exposes `bridge_getClaimProof`, `bridge_getLatestRoot` and
+// `bridge_getPendingClaims`, letting wallets build an L1 claim transaction
+// without running their own L1-info-tree indexer.
+type BridgeClaimAPI struct {
+	indexer *mysql.BridgeIndexer
+}
+
+// NewBridgeClaimAPI creates a BridgeClaimAPI serving proofs out of indexer.
+func NewBridgeClaimAPI(indexer *mysql.BridgeIndexer) *BridgeClaimAPI {
+	return &BridgeClaimAPI{indexer: indexer}
+}
+
+// GetClaimProof returns the Merkle path, root and leaf index proving
+// inclusion of the withdrawal message identified by hash (a "0x"-prefixed
+// hex string) in the L1 info tree.
+func (api *BridgeClaimAPI) GetClaimProof(hash string) (*ClaimProofResult, error) {
+	proof, err := api.indexer.GetClaimProof(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	path := make([]string, len(proof.Path))
+	for i, node := range proof.Path {
+		path[i] = "0x" + hex.EncodeToString(node[:])
+	}
+
+	return &ClaimProofResult{
+		Path:      path,
+		Root:      "0x" + hex.EncodeToString(proof.Root[:]),
+		LeafIndex: proof.LeafIndex,
+	}, nil
+}
+
+// GetLatestRoot returns the current root of the L1 info tree.
+func (api *BridgeClaimAPI) GetLatestRoot() (string, error) {
+	root, err := api.indexer.GetLatestRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return "0x" + hex.EncodeToString(root[:]), nil
+}
+
+// GetPendingClaims returns unclaimed withdrawal messages sent to address,
+// paginated by offset/limit.
+func (api *BridgeClaimAPI) GetPendingClaims(address string, offset, limit int) ([]PendingClaimResult, error) {
+	if limit <= 0 || limit > 1000 {
+		return nil, errors.New("limit must be in range (0, 1000]")
+	}
+
+	claims, err := api.indexer.GetPendingClaims(address, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]PendingClaimResult, len(claims))
+	for i, claim := range claims {
+		results[i] = PendingClaimResult{
+			MessageHash: claim.MessageHash,
+			Sender:      claim.Sender,
+			Recipient:   claim.Recipient,
+			LeafIndex:   claim.LeafIndex,
+		}
+	}
+
+	return results, nil
+}
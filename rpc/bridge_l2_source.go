@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/conflux-chain/conflux-infura/store/mysql"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+)
+
+// appendMessageEventSig is the topic0 of the L2ToL1MessagePasser event
+// recording a new outbound message, `AppendMessage(uint256,bytes32,address,address)`.
+var appendMessageEventSig = crypto.Keccak256Hash([]byte("AppendMessage(uint256,bytes32,address,address)"))
+
+// ethL2MessageSource implements mysql.L2MessageSource by filtering
+// L2ToL1MessagePasser logs off an L2 full node over JSON-RPC.
+type ethL2MessageSource struct {
+	client            *ethclient.Client
+	messagePasserAddr common.Address
+}
+
+// NewEthL2MessageSource dials l2NodeURL and watches messagePasserAddr for
+// outbound L2-to-L1 messages.
+func NewEthL2MessageSource(l2NodeURL string, messagePasserAddr common.Address) (mysql.L2MessageSource, error) {
+	client, err := ethclient.Dial(l2NodeURL)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to dial L2 node")
+	}
+
+	return &ethL2MessageSource{client: client, messagePasserAddr: messagePasserAddr}, nil
+}
+
+// MessagesInRange implements mysql.L2MessageSource.
+func (s *ethL2MessageSource) MessagesInRange(ctx context.Context, fromBlock, toBlock uint64) ([]mysql.L2Message, error) {
+	logs, err := s.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{s.messagePasserAddr},
+		Topics:    [][]common.Hash{{appendMessageEventSig}},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to filter L2ToL1MessagePasser logs")
+	}
+
+	messages := make([]mysql.L2Message, 0, len(logs))
+	for _, log := range logs {
+		msg, err := decodeAppendMessageLog(log)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to decode log at block %v", log.BlockNumber)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// LatestBlock implements mysql.L2MessageSource.
+func (s *ethL2MessageSource) LatestBlock(ctx context.Context) (uint64, error) {
+	header, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Number.Uint64(), nil
+}
+
+// BlockHash implements mysql.L2MessageSource.
+func (s *ethL2MessageSource) BlockHash(ctx context.Context, blockNum uint64) (string, error) {
+	header, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNum))
+	if err != nil {
+		return "", err
+	}
+
+	return header.Hash().Hex(), nil
+}
+
+// decodeAppendMessageLog extracts the message hash and sender/recipient from
+// an AppendMessage log: topic1 is the message hash, data holds sender and
+// recipient as two left-padded 32-byte words.
+func decodeAppendMessageLog(log types.Log) (mysql.L2Message, error) {
+	if len(log.Topics) < 2 {
+		return mysql.L2Message{}, errors.New("malformed AppendMessage log: missing message hash topic")
+	}
+
+	if len(log.Data) < 64 {
+		return mysql.L2Message{}, errors.New("malformed AppendMessage log: missing sender/recipient data")
+	}
+
+	var hash [32]byte
+	copy(hash[:], log.Topics[1].Bytes())
+
+	sender := common.BytesToAddress(log.Data[0:32]).Hex()
+	recipient := common.BytesToAddress(log.Data[32:64]).Hex()
+
+	return mysql.L2Message{
+		Hash:      hash,
+		Sender:    sender,
+		Recipient: recipient,
+		BlockNum:  log.BlockNumber,
+	}, nil
+}
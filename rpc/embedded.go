@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+)
+
+// Embedded runs an rpc.Server in-process, for Go tests that need a real
+// listening endpoint without a full staging deploy. Start always runs the
+// blocking serve loop in its own goroutine, feeding a buffered error channel
+// the caller is never required to read from immediately - the same class of
+// "caller forgot to drain the channel and deadlocked" bug fixed in other Go
+// RPC servers.
+type Embedded struct {
+	server *rpc.Server
+	addr   string
+
+	listener net.Listener
+	cancel   context.CancelFunc
+}
+
+// NewEmbedded wraps server so it can be started on addr (use "127.0.0.1:0"
+// to have the OS pick a free port).
+func NewEmbedded(server *rpc.Server, addr string) *Embedded {
+	if len(addr) == 0 {
+		addr = "127.0.0.1:0"
+	}
+
+	return &Embedded{server: server, addr: addr}
+}
+
+// Start binds a listener and serves e.server on it in a dedicated goroutine,
+// returning the resolved endpoint immediately and an error channel fed
+// exactly once with the serve loop's terminal error (nil on a clean Stop).
+// The channel is buffered so Start never blocks even if nobody reads it.
+func (e *Embedded) Start(ctx context.Context) (string, <-chan error) {
+	errCh := make(chan error, 1)
+
+	lis, err := net.Listen("tcp", e.addr)
+	if err != nil {
+		errCh <- errors.WithMessage(err, "failed to listen for embedded rpc server")
+		return "", errCh
+	}
+
+	e.listener = lis
+
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	go func() {
+		<-ctx.Done()
+		e.listener.Close()
+	}()
+
+	go func() {
+		err := http.Serve(lis, e.server)
+		if err != nil && errors.Is(err, net.ErrClosed) {
+			err = nil
+		}
+
+		errCh <- err
+	}()
+
+	endpoint := "http://" + lis.Addr().String()
+
+	logrus.WithField("endpoint", endpoint).Info("Embedded rpc server started")
+
+	return endpoint, errCh
+}
+
+// Stop cancels the context derived from the one passed to Start, which is
+// what actually closes the listener (via the goroutine Start spawned to wait
+// on it) and causes the serve goroutine to exit and report on the error
+// channel returned by Start. This way a caller that started with
+// context.Background() and only ever calls Stop still cleans up that
+// goroutine, instead of leaking it for the process lifetime.
+func (e *Embedded) Stop(ctx context.Context) error {
+	if e.listener == nil {
+		return nil
+	}
+
+	e.cancel()
+
+	return nil
+}
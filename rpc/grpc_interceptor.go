@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/scroll-tech/rpc-gateway/util/rate"
+	"github.com/scroll-tech/rpc-gateway/util/rpc/handlers"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcIPExtractor derives the caller's IP address for a gRPC call the same
+// way httpMiddleware does for JSON-RPC: prefer a forwarded-for style header,
+// falling back to the peer's transport address.
+func grpcIPExtractor(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(handlers.HeaderKeyIPAddress); len(vals) > 0 && len(vals[0]) > 0 {
+			return vals[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+
+	return "unknown_ip"
+}
+
+// unaryRateLimitInterceptor enforces registry's rate limit keyed by caller IP,
+// the gRPC equivalent of httpMiddleware's rate limiting for JSON-RPC.
+func unaryRateLimitInterceptor(registry *rate.Registry) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ip := grpcIPExtractor(ctx)
+
+		if !registry.Allow(ip, info.FullMethod) {
+			logrus.WithFields(logrus.Fields{
+				"ip":     ip,
+				"method": info.FullMethod,
+			}).Warn("gRPC request rejected by rate limiter")
+
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// streamRateLimitInterceptor is the streaming counterpart of
+// unaryRateLimitInterceptor, used for EthGetLogsStream-style methods.
+func streamRateLimitInterceptor(registry *rate.Registry) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+	) error {
+		ip := grpcIPExtractor(ss.Context())
+
+		if !registry.Allow(ip, info.FullMethod) {
+			logrus.WithFields(logrus.Fields{
+				"ip":     ip,
+				"method": info.FullMethod,
+			}).Warn("gRPC stream rejected by rate limiter")
+
+			return status.Errorf(codes.ResourceExhausted, "rate limit exceeded")
+		}
+
+		return handler(srv, ss)
+	}
+}
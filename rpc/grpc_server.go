@@ -0,0 +1,410 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	gwruntime "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/pkg/errors"
+	infuraNode "github.com/scroll-tech/rpc-gateway/node"
+	"github.com/scroll-tech/rpc-gateway/rpc/handler"
+	"github.com/scroll-tech/rpc-gateway/rpc/pb"
+	"github.com/scroll-tech/rpc-gateway/util/rate"
+	"github.com/scroll-tech/rpc-gateway/util/rpc"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// GrpcServerConfig configures a gRPC server started alongside (or instead
+// of) the JSON-RPC server, including its grpc-gateway HTTP/JSON reverse proxy.
+type GrpcServerConfig struct {
+	Endpoint        string `default:":32539"`
+	GatewayEndpoint string `default:":32540"`
+}
+
+// apiGrpcBridge dispatches gRPC calls through an in-process go-ethereum RPC
+// client bound directly to the rpc.API.Service objects registered for the
+// JSON-RPC server. Params and results travel as raw JSON, matching the wire
+// format TransactionArgs/FilterCriteria/hexutil types already marshal to, so
+// this never needs its own copy of those typed arguments - it drives the
+// exact same reflection-based method dispatch (go-ethereum's rpc.Server)
+// that the JSON-RPC HTTP handler drives, on the exact same Service values.
+// There is no second implementation to drift out of sync.
+type apiGrpcBridge struct {
+	client *gethrpc.Client
+}
+
+// newApiGrpcBridge registers apis' Service objects with a fresh in-process
+// go-ethereum rpc.Server and returns a client dialed straight into it.
+func newApiGrpcBridge(apis []rpc.API) (*apiGrpcBridge, error) {
+	gethServer := gethrpc.NewServer()
+
+	for _, api := range apis {
+		if err := gethServer.RegisterName(api.Namespace, api.Service); err != nil {
+			return nil, errors.WithMessagef(err, "failed to register %v api for grpc dispatch", api.Namespace)
+		}
+	}
+
+	return &apiGrpcBridge{client: gethrpc.DialInProc(gethServer)}, nil
+}
+
+// call invokes method with paramsJSON passed through verbatim (each element
+// must already be a JSON value, e.g. `"latest"` or `{"to":"0x..."}`) and
+// returns the raw JSON result exactly as the JSON-RPC server would have.
+func (b *apiGrpcBridge) call(ctx context.Context, method string, paramsJSON ...string) (json.RawMessage, error) {
+	args := make([]interface{}, len(paramsJSON))
+	for i, p := range paramsJSON {
+		args[i] = json.RawMessage(p)
+	}
+
+	var raw json.RawMessage
+	if err := b.client.CallContext(ctx, &raw, method, args...); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+type evmSpaceGrpcServer struct {
+	pb.UnimplementedEvmSpaceServer
+
+	bridge *apiGrpcBridge
+}
+
+func (s *evmSpaceGrpcServer) EthCall(ctx context.Context, req *pb.EthCallRequest) (*pb.EthCallResponse, error) {
+	raw, err := s.bridge.call(ctx, "eth_call", req.GetCallArgsJson(), req.GetBlockNrOrHash())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.EthCallResponse{Result: string(raw)}, nil
+}
+
+func (s *evmSpaceGrpcServer) EthGetLogs(ctx context.Context, req *pb.EthGetLogsRequest) (*pb.EthGetLogsResponse, error) {
+	logs, err := s.fetchLogs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.EthGetLogsResponse{Logs: make([]*pb.EthLog, len(logs))}
+	for i, logJson := range logs {
+		resp.Logs[i] = &pb.EthLog{LogJson: string(logJson)}
+	}
+
+	return resp, nil
+}
+
+// defaultLogsStreamChunkBlocks is the block range each EthGetLogsStream fetch
+// covers when the filter's fromBlock/toBlock are both explicit block
+// numbers, so logs are flushed to the client in chunks as they're fetched
+// instead of only after an eth_getLogs call spanning the whole range returns.
+const defaultLogsStreamChunkBlocks = 2000
+
+// blockRange is an inclusive [From, To] block number range.
+type blockRange struct {
+	From uint64
+	To   uint64
+}
+
+// EthGetLogsStream streams matching logs chunk by chunk as they are fetched,
+// instead of buffering the full result set, for clients backfilling large
+// ranges. Chunking requires the filter's fromBlock/toBlock to both be
+// explicit block numbers; when either is a tag (e.g. "latest") or absent,
+// the range isn't known upfront and this falls back to a single eth_getLogs
+// call, same as EthGetLogs.
+func (s *evmSpaceGrpcServer) EthGetLogsStream(req *pb.EthGetLogsRequest, stream pb.EvmSpace_EthGetLogsStreamServer) error {
+	chunks, chunkable, err := planLogsStreamChunks(req.GetFilterJson(), defaultLogsStreamChunkBlocks)
+	if err != nil {
+		return err
+	}
+
+	if !chunkable {
+		logs, err := s.fetchLogs(stream.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		return sendLogs(stream, logs)
+	}
+
+	for _, r := range chunks {
+		filterJSON, err := withBlockRange(req.GetFilterJson(), r)
+		if err != nil {
+			return err
+		}
+
+		logs, err := s.fetchLogs(stream.Context(), &pb.EthGetLogsRequest{FilterJson: filterJSON})
+		if err != nil {
+			return err
+		}
+
+		if err := sendLogs(stream, logs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendLogs streams each log to stream individually, so the caller's flush
+// granularity is per-chunk rather than per-whole-response.
+func sendLogs(stream pb.EvmSpace_EthGetLogsStreamServer, logs []json.RawMessage) error {
+	for _, logJson := range logs {
+		if err := stream.Send(&pb.EthLog{LogJson: string(logJson)}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// planLogsStreamChunks splits filterJSON's fromBlock/toBlock into
+// chunkSize-block ranges. It reports chunkable=false when fromBlock/toBlock
+// are absent, are block tags (e.g. "latest"/"pending") rather than explicit
+// numbers, or describe an empty/inverted range - in all of those cases the
+// caller should fall back to a single unchunked fetch.
+func planLogsStreamChunks(filterJSON string, chunkSize uint64) ([]blockRange, bool, error) {
+	var filter struct {
+		FromBlock *string `json:"fromBlock"`
+		ToBlock   *string `json:"toBlock"`
+	}
+
+	if err := json.Unmarshal([]byte(filterJSON), &filter); err != nil {
+		return nil, false, errors.WithMessage(err, "failed to unmarshal filter_json")
+	}
+
+	from, ok := parseHexBlockNumber(filter.FromBlock)
+	if !ok {
+		return nil, false, nil
+	}
+
+	to, ok := parseHexBlockNumber(filter.ToBlock)
+	if !ok {
+		return nil, false, nil
+	}
+
+	if to < from {
+		return nil, false, nil
+	}
+
+	var chunks []blockRange
+	for bn := from; bn <= to; bn += chunkSize {
+		end := bn + chunkSize - 1
+		if end > to {
+			end = to
+		}
+
+		chunks = append(chunks, blockRange{From: bn, To: end})
+	}
+
+	return chunks, true, nil
+}
+
+// parseHexBlockNumber decodes a "0x"-prefixed block number, reporting false
+// for a nil/empty value or a block tag (e.g. "latest", "pending"), neither
+// of which describe a range that can be chunked upfront.
+func parseHexBlockNumber(v *string) (uint64, bool) {
+	if v == nil || len(*v) == 0 {
+		return 0, false
+	}
+
+	bn, err := hexutil.DecodeUint64(*v)
+	if err != nil {
+		return 0, false
+	}
+
+	return bn, true
+}
+
+// withBlockRange returns filterJSON with fromBlock/toBlock overridden to r,
+// leaving every other field (address, topics, ...) untouched.
+func withBlockRange(filterJSON string, r blockRange) (string, error) {
+	var filter map[string]interface{}
+	if err := json.Unmarshal([]byte(filterJSON), &filter); err != nil {
+		return "", errors.WithMessage(err, "failed to unmarshal filter_json")
+	}
+
+	filter["fromBlock"] = hexutil.Uint64(r.From).String()
+	filter["toBlock"] = hexutil.Uint64(r.To).String()
+
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to marshal filter_json")
+	}
+
+	return string(encoded), nil
+}
+
+func (s *evmSpaceGrpcServer) fetchLogs(ctx context.Context, req *pb.EthGetLogsRequest) ([]json.RawMessage, error) {
+	raw, err := s.bridge.call(ctx, "eth_getLogs", req.GetFilterJson())
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []json.RawMessage
+	if err := json.Unmarshal(raw, &logs); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal eth_getLogs result")
+	}
+
+	return logs, nil
+}
+
+func (s *evmSpaceGrpcServer) EthBlockNumber(ctx context.Context, req *pb.EthBlockNumberRequest) (*pb.EthBlockNumberResponse, error) {
+	raw, err := s.bridge.call(ctx, "eth_blockNumber")
+	if err != nil {
+		return nil, err
+	}
+
+	var bn string
+	if err := json.Unmarshal(raw, &bn); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal eth_blockNumber result")
+	}
+
+	return &pb.EthBlockNumberResponse{BlockNumber: bn}, nil
+}
+
+// MustNewEvmSpaceGrpcServer builds a gRPC server that dispatches through the
+// same EthAPI Service objects used by MustNewEvmSpaceServer, so JSON-RPC and
+// gRPC clients share exactly one implementation per method. It panics via
+// logrus.Fatal on setup failure, matching the Must* convention used
+// throughout this package.
+func MustNewEvmSpaceGrpcServer(
+	router infuraNode.Router, exposedModules []string, option ...EthAPIOption,
+) *grpc.Server {
+	clientProvider := infuraNode.NewEthClientProvider(router)
+
+	allApis, err := evmSpaceApis(clientProvider, option...)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to new EVM space gRPC server")
+	}
+
+	exposedApis, err := filterExposedApis(allApis, exposedModules)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to new EVM space gRPC server with bad exposed modules")
+	}
+
+	bridge, err := newApiGrpcBridge(exposedApis)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to new EVM space gRPC server")
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryRateLimitInterceptor(rate.DefaultRegistryEth)),
+		grpc.ChainStreamInterceptor(streamRateLimitInterceptor(rate.DefaultRegistryEth)),
+	)
+
+	pb.RegisterEvmSpaceServer(server, &evmSpaceGrpcServer{bridge: bridge})
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+	return server
+}
+
+type nativeSpaceGrpcServer struct {
+	pb.UnimplementedNativeSpaceServer
+
+	bridge *apiGrpcBridge
+}
+
+func (s *nativeSpaceGrpcServer) CfxEpochNumber(ctx context.Context, req *pb.CfxEpochNumberRequest) (*pb.CfxEpochNumberResponse, error) {
+	var params []string
+	if tag := req.GetEpochTag(); len(tag) > 0 {
+		encoded, err := json.Marshal(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, string(encoded))
+	}
+
+	raw, err := s.bridge.call(ctx, "cfx_epochNumber", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	var epoch string
+	if err := json.Unmarshal(raw, &epoch); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal cfx_epochNumber result")
+	}
+
+	return &pb.CfxEpochNumberResponse{EpochNumber: epoch}, nil
+}
+
+func (s *nativeSpaceGrpcServer) CfxGetLogs(ctx context.Context, req *pb.CfxGetLogsRequest) (*pb.CfxGetLogsResponse, error) {
+	raw, err := s.bridge.call(ctx, "cfx_getLogs", req.GetFilterJson())
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []json.RawMessage
+	if err := json.Unmarshal(raw, &logs); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal cfx_getLogs result")
+	}
+
+	resp := &pb.CfxGetLogsResponse{LogJson: make([]string, len(logs))}
+	for i, logJson := range logs {
+		resp.LogJson[i] = string(logJson)
+	}
+
+	return resp, nil
+}
+
+// MustNewNativeSpaceGrpcServer builds a gRPC server dispatching through the
+// same Service objects used by MustNewNativeSpaceServer.
+func MustNewNativeSpaceGrpcServer(
+	router infuraNode.Router, gashandler *handler.GasStationHandler,
+	exposedModules []string, option ...CfxAPIOption,
+) *grpc.Server {
+	clientProvider := infuraNode.NewCfxClientProvider(router)
+	allApis := nativeSpaceApis(clientProvider, gashandler, option...)
+
+	exposedApis, err := filterExposedApis(allApis, exposedModules)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to new native space gRPC server with bad exposed modules")
+	}
+
+	bridge, err := newApiGrpcBridge(exposedApis)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to new native space gRPC server")
+	}
+
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryRateLimitInterceptor(rate.DefaultRegistryCfx)),
+	)
+
+	pb.RegisterNativeSpaceServer(server, &nativeSpaceGrpcServer{bridge: bridge})
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+
+	return server
+}
+
+// ServeGateway starts a grpc-gateway reverse proxy translating HTTP/JSON
+// requests into gRPC calls against config.Endpoint, letting existing HTTP
+// clients use the same methods without speaking gRPC directly.
+func ServeGateway(ctx context.Context, config GrpcServerConfig) error {
+	mux := gwruntime.NewServeMux()
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+
+	if err := pb.RegisterEvmSpaceHandlerFromEndpoint(ctx, mux, config.Endpoint, dialOpts); err != nil {
+		return errors.WithMessage(err, "failed to register EvmSpace grpc-gateway handler")
+	}
+
+	if err := pb.RegisterNativeSpaceHandlerFromEndpoint(ctx, mux, config.Endpoint, dialOpts); err != nil {
+		return errors.WithMessage(err, "failed to register NativeSpace grpc-gateway handler")
+	}
+
+	lis, err := net.Listen("tcp", config.GatewayEndpoint)
+	if err != nil {
+		return errors.WithMessage(err, "failed to listen on grpc-gateway endpoint")
+	}
+
+	logrus.WithField("endpoint", config.GatewayEndpoint).Info("grpc-gateway reverse proxy started")
+
+	return http.Serve(lis, mux)
+}
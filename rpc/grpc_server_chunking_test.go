@@ -0,0 +1,58 @@
+package rpc
+
+import "testing"
+
+func TestPlanLogsStreamChunksSplitsExplicitRange(t *testing.T) {
+	chunks, chunkable, err := planLogsStreamChunks(`{"fromBlock":"0x0","toBlock":"0x1388"}`, 2000)
+	if err != nil {
+		t.Fatalf("planLogsStreamChunks() error = %v", err)
+	}
+	if !chunkable {
+		t.Fatalf("expected an explicit fromBlock/toBlock range to be chunkable")
+	}
+
+	want := []blockRange{{From: 0, To: 1999}, {From: 2000, To: 4999}, {From: 5000, To: 5000}}
+	if len(chunks) != len(want) {
+		t.Fatalf("chunks = %+v, want %+v", chunks, want)
+	}
+	for i, c := range chunks {
+		if c != want[i] {
+			t.Fatalf("chunks[%d] = %+v, want %+v", i, c, want[i])
+		}
+	}
+}
+
+func TestPlanLogsStreamChunksFallsBackOnBlockTag(t *testing.T) {
+	_, chunkable, err := planLogsStreamChunks(`{"fromBlock":"0x0","toBlock":"latest"}`, 2000)
+	if err != nil {
+		t.Fatalf("planLogsStreamChunks() error = %v", err)
+	}
+	if chunkable {
+		t.Fatalf("expected a block-tag toBlock to fall back to an unchunked fetch")
+	}
+}
+
+func TestPlanLogsStreamChunksFallsBackWhenAbsent(t *testing.T) {
+	_, chunkable, err := planLogsStreamChunks(`{"address":"0xabc"}`, 2000)
+	if err != nil {
+		t.Fatalf("planLogsStreamChunks() error = %v", err)
+	}
+	if chunkable {
+		t.Fatalf("expected a filter with no fromBlock/toBlock to fall back to an unchunked fetch")
+	}
+}
+
+func TestWithBlockRangePreservesOtherFields(t *testing.T) {
+	got, err := withBlockRange(`{"address":"0xabc","fromBlock":"0x0","toBlock":"latest"}`, blockRange{From: 10, To: 19})
+	if err != nil {
+		t.Fatalf("withBlockRange() error = %v", err)
+	}
+
+	chunks, chunkable, err := planLogsStreamChunks(got, 2000)
+	if err != nil {
+		t.Fatalf("planLogsStreamChunks() on withBlockRange output error = %v", err)
+	}
+	if !chunkable || len(chunks) != 1 || chunks[0] != (blockRange{From: 10, To: 19}) {
+		t.Fatalf("withBlockRange() did not round-trip the overridden range, got %v", got)
+	}
+}
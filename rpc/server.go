@@ -1,6 +1,10 @@
 package rpc
 
 import (
+	"context"
+
+	"github.com/conflux-chain/conflux-infura/store/mysql"
+	"github.com/ethereum/go-ethereum/common"
 	infuraNode "github.com/scroll-tech/rpc-gateway/node"
 	"github.com/scroll-tech/rpc-gateway/rpc/handler"
 	"github.com/scroll-tech/rpc-gateway/util/rate"
@@ -69,6 +73,14 @@ type CfxBridgeServerConfig struct {
 	CfxNode        string
 	ExposedModules []string
 	Endpoint       string `default:":32537"`
+
+	// L2ToL1MessagePasser is the address of the Scroll L2ToL1MessagePasser
+	// contract whose withdrawal messages get indexed into the claim tree.
+	// Leave empty to disable the withdrawal claim proof API.
+	L2ToL1MessagePasser string
+	// BridgeIndexerDb is the mysql database the withdrawal Merkle tree and
+	// indexed messages are persisted into.
+	BridgeIndexerDb *mysql.Config
 }
 
 func MustNewNativeSpaceBridgeServer(config *CfxBridgeServerConfig) *rpc.Server {
@@ -77,6 +89,14 @@ func MustNewNativeSpaceBridgeServer(config *CfxBridgeServerConfig) *rpc.Server {
 		logrus.WithError(err).Fatal("Failed to new CFX bridge RPC server")
 	}
 
+	if claimApi := mustNewBridgeClaimApi(config); claimApi != nil {
+		allApis = append(allApis, rpc.API{
+			Namespace: bridgeClaimApiModule,
+			Version:   "1.0",
+			Service:   claimApi,
+		})
+	}
+
 	exposedApis, err := filterExposedApis(allApis, config.ExposedModules)
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to new CFX bridge RPC server with bad exposed modules")
@@ -84,3 +104,22 @@ func MustNewNativeSpaceBridgeServer(config *CfxBridgeServerConfig) *rpc.Server {
 
 	return rpc.MustNewServer(nativeSpaceBridgeRpcServerName, exposedApis)
 }
+
+// mustNewBridgeClaimApi wires up the L1-info-tree indexer and claim proof API
+// alongside the bridge server, or returns nil if withdrawal claim proofs are
+// not configured for this deployment.
+func mustNewBridgeClaimApi(config *CfxBridgeServerConfig) *BridgeClaimAPI {
+	if len(config.L2ToL1MessagePasser) == 0 || config.BridgeIndexerDb == nil {
+		return nil
+	}
+
+	source, err := NewEthL2MessageSource(config.EthNode, common.HexToAddress(config.L2ToL1MessagePasser))
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create L2 message source for bridge indexer")
+	}
+
+	indexer := mysql.NewBridgeIndexer(config.BridgeIndexerDb, source)
+	indexer.Start(context.Background())
+
+	return NewBridgeClaimAPI(indexer)
+}
@@ -0,0 +1,140 @@
+// Package rpctest spins up embedded RPC servers wired to in-process fake
+// upstream nodes, so the middleware stack (httpMiddleware, rate limiters, IP
+// extraction) can be exercised from Go tests without a full staging deploy.
+package rpctest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/scroll-tech/rpc-gateway/node"
+	infurarpc "github.com/scroll-tech/rpc-gateway/rpc"
+)
+
+// FakeUpstream is an in-process stand-in for a full node: it answers every
+// JSON-RPC request with a user-supplied result, keyed by method name, so
+// tests can control exactly what "the full node" returns without dialing
+// anything real.
+type FakeUpstream struct {
+	server *httptest.Server
+}
+
+type jsonrpcRequest struct {
+	Method string          `json:"method"`
+	ID     json.RawMessage `json:"id"`
+}
+
+// NewFakeUpstream starts a fake upstream node whose JSON-RPC responses are
+// taken verbatim from results, keyed by method name. A method not present in
+// results is answered with a JSON-RPC error.
+func NewFakeUpstream(results map[string]interface{}) *FakeUpstream {
+	u := &FakeUpstream{}
+
+	u.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, ok := results[req.Method]
+		if !ok {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, req.ID)
+			return
+		}
+
+		resp := struct {
+			Jsonrpc string          `json:"jsonrpc"`
+			ID      json.RawMessage `json:"id"`
+			Result  interface{}     `json:"result"`
+		}{"2.0", req.ID, result}
+
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	return u
+}
+
+// URL returns the endpoint a node.Router should route to in order to reach
+// this fake upstream.
+func (u *FakeUpstream) URL() string {
+	return u.server.URL
+}
+
+// Close shuts down the underlying HTTP server.
+func (u *FakeUpstream) Close() {
+	u.server.Close()
+}
+
+// staticRouter always routes to a single fixed URL, regardless of group or
+// key, standing in for the hashring/load-balancing Router used in production.
+type staticRouter struct {
+	url string
+}
+
+func (r *staticRouter) Route(group node.Group, key []byte) string {
+	return r.url
+}
+
+// NewFakeRouter returns a node.Router that always routes to url, wiring an
+// embedded server to a single FakeUpstream.
+func NewFakeRouter(url string) node.Router {
+	return &staticRouter{url: url}
+}
+
+// Server is a started embedded RPC server plus its wired fake upstream,
+// ready for a test to dial.
+type Server struct {
+	Upstream *FakeUpstream
+	Endpoint string
+
+	embedded *infurarpc.Embedded
+	errCh    <-chan error
+}
+
+// StartEvmSpace starts an embedded EVM space RPC server wired to a fake
+// upstream that answers with upstreamResults, keyed by JSON-RPC method name.
+func StartEvmSpace(ctx context.Context, upstreamResults map[string]interface{}) (*Server, error) {
+	upstream := NewFakeUpstream(upstreamResults)
+	router := NewFakeRouter(upstream.URL())
+
+	server := infurarpc.MustNewEvmSpaceServer(router, nil)
+	embedded := infurarpc.NewEmbedded(server, "")
+
+	endpoint, errCh := embedded.Start(ctx)
+
+	return &Server{Upstream: upstream, Endpoint: endpoint, embedded: embedded, errCh: errCh}, nil
+}
+
+// StartNativeSpaceBridge starts an embedded bridge RPC server wired to fake
+// EthNode/CfxNode upstreams, with withdrawal claim proofs disabled so no
+// mysql dependency is required.
+func StartNativeSpaceBridge(ctx context.Context, upstreamResults map[string]interface{}) (*Server, error) {
+	upstream := NewFakeUpstream(upstreamResults)
+
+	server := infurarpc.MustNewNativeSpaceBridgeServer(&infurarpc.CfxBridgeServerConfig{
+		EthNode: upstream.URL(),
+		CfxNode: upstream.URL(),
+	})
+	embedded := infurarpc.NewEmbedded(server, "")
+
+	endpoint, errCh := embedded.Start(ctx)
+
+	return &Server{Upstream: upstream, Endpoint: endpoint, embedded: embedded, errCh: errCh}, nil
+}
+
+// Stop tears down the embedded server and its fake upstream.
+func (s *Server) Stop(ctx context.Context) error {
+	defer s.Upstream.Close()
+
+	return s.embedded.Stop(ctx)
+}
+
+// Err returns the embedded server's terminal error channel, as returned by
+// Embedded.Start.
+func (s *Server) Err() <-chan error {
+	return s.errCh
+}
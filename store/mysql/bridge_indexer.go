@@ -0,0 +1,295 @@
+package mysql
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// defaultBridgeIndexerPollInterval is how often the indexer checks for new
+// L2 blocks when there is no head-block subscription available.
+const defaultBridgeIndexerPollInterval = 3 * time.Second
+
+// L2Message is a single Scroll MessageQueue/L2ToL1MessagePasser event to be
+// folded into the withdrawal Merkle tree.
+type L2Message struct {
+	Hash      [32]byte
+	Sender    string
+	Recipient string
+	BlockNum  uint64
+}
+
+// L2MessageSource yields indexed messages from the Scroll L2 chain. It is
+// implemented by a thin wrapper around an `ethclient.Client` filtering
+// MessageQueue/L2ToL1MessagePasser logs; kept as an interface here so the
+// indexer's tree/reorg bookkeeping can be tested without a live L2 node.
+type L2MessageSource interface {
+	// MessagesInRange returns every message emitted in [fromBlock, toBlock].
+	MessagesInRange(ctx context.Context, fromBlock, toBlock uint64) ([]L2Message, error)
+	// LatestBlock returns the current L2 head block number.
+	LatestBlock(ctx context.Context) (uint64, error)
+	// BlockHash returns the canonical hash of blockNum, used to detect reorgs.
+	BlockHash(ctx context.Context, blockNum uint64) (string, error)
+}
+
+// ClaimProof is the Merkle path, root and leaf index proving a message's
+// inclusion in the withdrawal tree.
+type ClaimProof struct {
+	Path      [][32]byte
+	Root      [32]byte
+	LeafIndex uint64
+}
+
+// PendingClaim describes an indexed, not-yet-claimed withdrawal message.
+type PendingClaim struct {
+	MessageHash string
+	Sender      string
+	Recipient   string
+	LeafIndex   uint64
+}
+
+// BridgeIndexer is a background service that folds Scroll L2-to-L1 messages
+// into an append-only Merkle tree, tolerating reorgs, and serves claim proofs
+// built from it so wallets can construct an L1 claim transaction without
+// running their own indexer.
+type BridgeIndexer struct {
+	db     *gorm.DB
+	tree   *l1InfoTreeStore
+	msgs   *bridgeMessageStore
+	source L2MessageSource
+
+	pollInterval time.Duration
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewBridgeIndexer creates a BridgeIndexer persisting into the database
+// described by dbCfg and reading messages from source.
+func NewBridgeIndexer(dbCfg *Config, source L2MessageSource) *BridgeIndexer {
+	db := dbCfg.mustNewDB(dbCfg.Database)
+
+	return &BridgeIndexer{
+		db:           db,
+		tree:         newL1InfoTreeStore(db),
+		msgs:         newBridgeMessageStore(db),
+		source:       source,
+		pollInterval: defaultBridgeIndexerPollInterval,
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// Start begins polling the L2 chain for new messages in a dedicated
+// goroutine. It returns immediately; call Stop to shut it down.
+func (bi *BridgeIndexer) Start(ctx context.Context) {
+	go bi.run(ctx)
+}
+
+// Stop signals the indexing loop to exit and waits for it to finish.
+func (bi *BridgeIndexer) Stop() {
+	close(bi.stopCh)
+	<-bi.doneCh
+}
+
+func (bi *BridgeIndexer) run(ctx context.Context) {
+	defer close(bi.doneCh)
+
+	ticker := time.NewTicker(bi.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bi.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := bi.syncOnce(ctx); err != nil {
+				logrus.WithError(err).Error("Bridge indexer sync iteration failed")
+			}
+		}
+	}
+}
+
+func (bi *BridgeIndexer) syncOnce(ctx context.Context) error {
+	checkpoint, err := bi.tree.loadOrInitCheckpoint(bi.db)
+	if err != nil {
+		return errors.WithMessage(err, "failed to load indexer checkpoint")
+	}
+
+	fromBlock := uint64(0)
+	if len(checkpoint.LastL2BlockHash) > 0 {
+		reorged, err := bi.detectReorg(ctx, checkpoint)
+		if err != nil {
+			return err
+		}
+
+		if reorged {
+			fromBlock = checkpoint.LastL2Block
+		} else {
+			fromBlock = checkpoint.LastL2Block + 1
+		}
+	}
+
+	head, err := bi.source.LatestBlock(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get L2 head block")
+	}
+
+	if fromBlock > head {
+		return nil
+	}
+
+	headHash, err := bi.source.BlockHash(ctx, head)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get hash for L2 head block %v", head)
+	}
+
+	messages, err := bi.source.MessagesInRange(ctx, fromBlock, head)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to fetch messages in range [%v, %v]", fromBlock, head)
+	}
+
+	err = bi.db.Transaction(func(dbTx *gorm.DB) error {
+		for _, msg := range messages {
+			leafIndex, err := bi.tree.AppendLeaf(dbTx, checkpoint, msg.Hash)
+			if err != nil {
+				return errors.WithMessage(err, "failed to append leaf")
+			}
+
+			record := &bridgeMessage{
+				MessageHash:   "0x" + hex.EncodeToString(msg.Hash[:]),
+				LeafIndex:     leafIndex,
+				Sender:        msg.Sender,
+				Recipient:     msg.Recipient,
+				L2BlockNumber: msg.BlockNum,
+			}
+
+			if err := bi.msgs.Pushn(dbTx, []*bridgeMessage{record}); err != nil {
+				return errors.WithMessage(err, "failed to persist bridge message")
+			}
+		}
+
+		// persist the new leaf-index/block/hash checkpoint together and
+		// atomically with the leaves and messages it describes, so a crash
+		// between flushing messages and advancing the checkpoint is
+		// impossible - either the whole batch lands, or none of it does.
+		checkpoint.LastL2Block = head
+		checkpoint.LastL2BlockHash = headHash
+
+		return bi.tree.SaveCheckpoint(dbTx, checkpoint)
+	})
+
+	// AppendLeaf's node writes are held back from the tree's frontier cache
+	// until we know whether this transaction actually committed, so a
+	// rolled-back batch can never leave the cache serving hashes for nodes
+	// that were never persisted (see l1InfoTreeStore.setNode).
+	if err != nil {
+		bi.tree.DiscardPending()
+		return err
+	}
+
+	bi.tree.CommitPending()
+	return nil
+}
+
+// detectReorg checks whether the L2 block the tree was last built up to is
+// still canonical, by comparing its freshly fetched hash against the one
+// recorded in checkpoint. A mismatch means that block (and everything after
+// it) was orphaned: the tree and message store are rolled back to just
+// before it and reorged=true is reported so the caller reprocesses it. A
+// genuine fetch error is returned as an error, not mistaken for a reorg.
+func (bi *BridgeIndexer) detectReorg(ctx context.Context, checkpoint *l1InfoTreeCheckpoint) (bool, error) {
+	currentHash, err := bi.source.BlockHash(ctx, checkpoint.LastL2Block)
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to fetch hash for block %v", checkpoint.LastL2Block)
+	}
+
+	if currentHash == checkpoint.LastL2BlockHash {
+		return false, nil
+	}
+
+	logrus.WithField("block", checkpoint.LastL2Block).Warn("Detected L2 reorg, rolling back bridge tree")
+
+	// translate the orphaned block-number boundary into the tree's
+	// leaf-index scale; RollbackFrom operates on leaf indexes, not blocks.
+	leafIndex, found, err := bi.msgs.MinLeafIndexFromBlock(checkpoint.LastL2Block)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to locate rollback leaf index")
+	}
+
+	if !found {
+		// no messages were ever indexed at or after the orphaned block, so
+		// there is nothing in the tree to roll back.
+		leafIndex = checkpoint.NextLeafIndex
+	}
+
+	err = bi.db.Transaction(func(dbTx *gorm.DB) error {
+		if err := bi.tree.RollbackFrom(dbTx, leafIndex); err != nil {
+			return err
+		}
+
+		return bi.msgs.RollbackFrom(dbTx, checkpoint.LastL2Block)
+	})
+	if err != nil {
+		bi.tree.DiscardPending()
+		return false, err
+	}
+
+	bi.tree.CommitPending()
+
+	checkpoint.NextLeafIndex = leafIndex
+
+	return true, nil
+}
+
+// GetClaimProof returns the Merkle path, root and leaf index proving
+// inclusion of the message identified by messageHash (a "0x"-prefixed hex
+// string), for building an L1 claim transaction.
+func (bi *BridgeIndexer) GetClaimProof(messageHash string) (*ClaimProof, error) {
+	msg, existed, err := bi.msgs.ByHash(messageHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !existed {
+		return nil, errors.Errorf("unknown message hash %v", messageHash)
+	}
+
+	path, root, err := bi.tree.GetClaimProof(msg.LeafIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClaimProof{Path: path, Root: root, LeafIndex: msg.LeafIndex}, nil
+}
+
+// GetLatestRoot returns the current root of the withdrawal Merkle tree.
+func (bi *BridgeIndexer) GetLatestRoot() ([32]byte, error) {
+	return bi.tree.LatestRoot()
+}
+
+// GetPendingClaims returns unclaimed messages sent to recipient, paginated.
+func (bi *BridgeIndexer) GetPendingClaims(recipient string, offset, limit int) ([]PendingClaim, error) {
+	messages, err := bi.msgs.Pending(recipient, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make([]PendingClaim, 0, len(messages))
+	for _, msg := range messages {
+		claims = append(claims, PendingClaim{
+			MessageHash: msg.MessageHash,
+			Sender:      msg.Sender,
+			Recipient:   msg.Recipient,
+			LeafIndex:   msg.LeafIndex,
+		})
+	}
+
+	return claims, nil
+}
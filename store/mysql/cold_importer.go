@@ -0,0 +1,192 @@
+package mysql
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/conflux-chain/conflux-infura/store"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// defaultColdImportBatchSize is the number of blocks assembled into a single
+// store.EpochData batch before it is flushed to mysql.
+const defaultColdImportBatchSize = 1000
+
+// ColdImporterConfig configures a ColdImporter run.
+type ColdImporterConfig struct {
+	// ChainDataDir points at the local geth/Scroll `chaindata` LevelDB directory.
+	ChainDataDir string
+
+	// StartingBlock is the first block number (inclusive) to import.
+	StartingBlock uint64
+	// EndingBlock is the last block number (inclusive) to import. Ignored when SyncAll is set.
+	EndingBlock uint64
+	// SyncAll imports from StartingBlock up to the highest block found in chaindata.
+	SyncAll bool
+
+	// ChainID is used to derive each receipt's sender/tx-hash/log-index
+	// linkage fields (see Receipts.DeriveFields); it must match the chain
+	// the chaindata directory was produced by.
+	ChainID uint64
+
+	// BatchSize is the number of blocks assembled per store.EpochData batch.
+	BatchSize int
+}
+
+// ColdImporter bulk loads headers, bodies, receipts and logs directly out of a
+// locally-mounted geth/Scroll ancient chaindata LevelDB, bypassing JSON-RPC
+// entirely. This is used to bootstrap a new gateway where syncing ~100M blocks
+// over RPC is infeasible.
+type ColdImporter struct {
+	config      ColdImporterConfig
+	chainConfig *params.ChainConfig
+
+	chainDb ethdb.Database
+
+	db           *gorm.DB
+	epochBlockMs *epochBlockMapStore
+	txStore      *transactionStore
+	blockStore   *blockStore
+	logStore     *logStore
+}
+
+// NewColdImporter creates a ColdImporter reading from the chaindata LevelDB at
+// importCfg.ChainDataDir and writing into the mysql database described by dbCfg.
+func NewColdImporter(dbCfg *Config, importCfg ColdImporterConfig) (*ColdImporter, error) {
+	ldb, err := leveldb.New(importCfg.ChainDataDir, 0, 0, "", true)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to open chaindata leveldb")
+	}
+
+	if importCfg.BatchSize == 0 {
+		importCfg.BatchSize = defaultColdImportBatchSize
+	}
+
+	db := dbCfg.mustNewDB(dbCfg.Database)
+
+	return &ColdImporter{
+		config:       importCfg,
+		chainConfig:  &params.ChainConfig{ChainID: new(big.Int).SetUint64(importCfg.ChainID)},
+		chainDb:      rawdb.NewDatabase(ldb),
+		db:           db,
+		epochBlockMs: newEpochBlockMapStore(db),
+		txStore:      newTransactionStore(db),
+		blockStore:   newBlockStore(db),
+		logStore:     newLogStore(db),
+	}, nil
+}
+
+// Run iterates the configured block range, assembles store.EpochData batches
+// and pushes them into the epoch/block/transaction/log stores.
+func (ci *ColdImporter) Run() error {
+	defer ci.chainDb.Close()
+
+	start := ci.config.StartingBlock
+	end := ci.config.EndingBlock
+
+	if ci.config.SyncAll {
+		head := rawdb.ReadHeadBlockHash(ci.chainDb)
+		headNum := rawdb.ReadHeaderNumber(ci.chainDb, head)
+		if headNum == nil {
+			return errors.New("failed to resolve head block number from chaindata")
+		}
+		end = *headNum
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"start": start,
+		"end":   end,
+	}).Info("Cold import started")
+
+	var batch []*store.EpochData
+
+	for bn := start; bn <= end; bn++ {
+		data, err := ci.readEpochData(bn)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to read block %v from chaindata", bn)
+		}
+
+		batch = append(batch, data)
+
+		if len(batch) >= ci.config.BatchSize || bn == end {
+			if err := ci.flush(batch); err != nil {
+				return errors.WithMessagef(err, "failed to flush batch ending at block %v", bn)
+			}
+
+			logrus.WithField("block", bn).Info("Cold import progress")
+			batch = batch[:0]
+		}
+	}
+
+	logrus.Info("Cold import completed")
+	return nil
+}
+
+// readEpochData reads the header, body, receipts and logs for block number bn
+// out of the chaindata LevelDB and assembles them into a store.EpochData.
+func (ci *ColdImporter) readEpochData(bn uint64) (*store.EpochData, error) {
+	var emptyHash common.Hash
+
+	hash := rawdb.ReadCanonicalHash(ci.chainDb, bn)
+	if hash == emptyHash {
+		return nil, fmt.Errorf("no canonical hash for block %v", bn)
+	}
+
+	header := rawdb.ReadHeader(ci.chainDb, hash, bn)
+	if header == nil {
+		return nil, fmt.Errorf("header not found for block %v", bn)
+	}
+
+	body := rawdb.ReadBody(ci.chainDb, hash, bn)
+	if body == nil {
+		return nil, fmt.Errorf("body not found for block %v", bn)
+	}
+
+	// ReadRawReceipts alone leaves BlockNumber/BlockHash/TxHash/
+	// TransactionIndex and each log's Index unset - DeriveFields (the same
+	// step ReadReceipts performs internally) fills them in from the block
+	// body, which is required for the imported logs to be usable by
+	// eth_getLogs at all.
+	receipts := rawdb.ReadRawReceipts(ci.chainDb, hash, bn)
+	if receipts == nil {
+		return nil, fmt.Errorf("receipts not found for block %v", bn)
+	}
+
+	if err := receipts.DeriveFields(ci.chainConfig, hash, bn, body.Transactions); err != nil {
+		return nil, errors.WithMessagef(err, "failed to derive receipt fields for block %v", bn)
+	}
+
+	return store.NewEpochData(header, body, receipts), nil
+}
+
+// flush pushes an assembled batch of epoch data into the epoch/block/tx/log
+// stores, relying on the same CreateInBatches paths used by the incremental
+// RPC sync path so that LogsTablePartitionRangeSize partitioning is honored.
+func (ci *ColdImporter) flush(batch []*store.EpochData) error {
+	return ci.db.Transaction(func(dbTx *gorm.DB) error {
+		if err := ci.epochBlockMs.Pushn(dbTx, batch); err != nil {
+			return errors.WithMessage(err, "failed to push epoch block map")
+		}
+
+		if err := ci.blockStore.Pushn(dbTx, batch); err != nil {
+			return errors.WithMessage(err, "failed to push blocks")
+		}
+
+		if err := ci.txStore.Pushn(dbTx, batch); err != nil {
+			return errors.WithMessage(err, "failed to push transactions")
+		}
+
+		if err := ci.logStore.Pushn(dbTx, batch); err != nil {
+			return errors.WithMessage(err, "failed to push logs")
+		}
+
+		return nil
+	})
+}
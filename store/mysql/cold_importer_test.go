@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestReadEpochDataProducesSaneEpochData exercises readEpochData against a
+// real chaindata db and asserts the resulting store.EpochData matches the
+// single-block-epoch contract epochBlockMapStore.Pushn (store_map_epoch_block.go)
+// already relies on: one block whose BlockNumber is the imported block number,
+// and a pivot block resolving to that same block's hash.
+func TestReadEpochDataProducesSaneEpochData(t *testing.T) {
+	to := common.HexToAddress("0x000000000000000000000000000000000000f1")
+	tx := types.NewTransaction(0, to, big.NewInt(0), 21000, big.NewInt(1), nil)
+
+	header := &types.Header{Number: big.NewInt(42), GasLimit: 21000}
+	body := &types.Body{Transactions: types.Transactions{tx}}
+
+	receipt := &types.Receipt{
+		Type:   types.LegacyTxType,
+		Status: types.ReceiptStatusSuccessful,
+		Logs: []*types.Log{
+			{Address: common.HexToAddress("0x000000000000000000000000000000000000f2")},
+		},
+		TxHash:  tx.Hash(),
+		GasUsed: 21000,
+	}
+	receipts := types.Receipts{receipt}
+
+	block := types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles)
+	hash := block.Hash()
+	bn := block.NumberU64()
+
+	ci := &ColdImporter{
+		chainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		chainDb:     rawdb.NewMemoryDatabase(),
+	}
+
+	rawdb.WriteHeader(ci.chainDb, header)
+	rawdb.WriteBody(ci.chainDb, hash, bn, body)
+	rawdb.WriteReceipts(ci.chainDb, hash, bn, receipts)
+	rawdb.WriteCanonicalHash(ci.chainDb, hash, bn)
+	t.Cleanup(func() { ci.chainDb.Close() })
+
+	data, err := ci.readEpochData(bn)
+	if err != nil {
+		t.Fatalf("readEpochData() error = %v", err)
+	}
+
+	if data.Number != bn {
+		t.Fatalf("EpochData.Number = %v, want %v", data.Number, bn)
+	}
+
+	if len(data.Blocks) != 1 {
+		t.Fatalf("expected a cold-imported block to produce a single-block epoch, got %v blocks", len(data.Blocks))
+	}
+
+	if got := data.Blocks[0].BlockNumber.ToInt().Uint64(); got != bn {
+		t.Fatalf("Blocks[0].BlockNumber = %v, want %v", got, bn)
+	}
+
+	pivot := data.GetPivotBlock()
+	if got := pivot.BlockNumber.ToInt().Uint64(); got != bn {
+		t.Fatalf("pivot block number = %v, want %v", got, bn)
+	}
+	if pivot.Hash.String() != hash.Hex() {
+		t.Fatalf("pivot block hash = %v, want %v", pivot.Hash.String(), hash.Hex())
+	}
+}
+
+// TestReadEpochDataMissingBlockErrors documents the not-found error path
+// epochBlockMapStore/Run rely on to stop a cold-import run at chaindata's
+// actual head rather than silently importing zero-value data.
+func TestReadEpochDataMissingBlockErrors(t *testing.T) {
+	ci := &ColdImporter{
+		chainConfig: &params.ChainConfig{ChainID: big.NewInt(1)},
+		chainDb:     rawdb.NewMemoryDatabase(),
+	}
+	t.Cleanup(func() { ci.chainDb.Close() })
+
+	if _, err := ci.readEpochData(1); err == nil {
+		t.Fatalf("expected an error reading a block absent from chaindata, got nil")
+	}
+}
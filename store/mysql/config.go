@@ -26,6 +26,15 @@ type Config struct {
 	ConnMaxLifetime time.Duration
 	MaxOpenConns    int
 	MaxIdleConns    int
+
+	// LogsPartitionHeadroom is how many rows of headroom the active logs
+	// partition must keep before PartitionManager reorganizes logsow to
+	// allocate the next range.
+	LogsPartitionHeadroom uint64
+	// LogsRetentionPartitions caps the number of logs partitions retained;
+	// once exceeded, PartitionManager drops the oldest one. 0 disables
+	// retention-based dropping.
+	LogsRetentionPartitions uint64
 }
 
 // NewConfigFromViper creates an instance of Config from Viper.
@@ -43,6 +52,9 @@ func NewConfigFromViper() (Config, bool) {
 		ConnMaxLifetime: viper.GetDuration("store.mysql.connMaxLifeTime"),
 		MaxOpenConns:    viper.GetInt("store.mysql.maxOpenConns"),
 		MaxIdleConns:    viper.GetInt("store.mysql.maxIdleConns"),
+
+		LogsPartitionHeadroom:   viper.GetUint64("store.mysql.logsPartitionHeadroom"),
+		LogsRetentionPartitions: viper.GetUint64("store.mysql.logsRetentionPartitions"),
 	}, true
 }
 
@@ -72,6 +84,10 @@ func (config *Config) MustOpenOrCreate(option StoreOption) store.Store {
 
 	logrus.Info("MySQL database initialized")
 
+	if config.LogsPartitionHeadroom > 0 {
+		NewPartitionManager(db, config.LogsPartitionHeadroom, config.LogsRetentionPartitions).Start()
+	}
+
 	return mustNewStore(db, option)
 }
 
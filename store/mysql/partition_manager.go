@@ -0,0 +1,197 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// defaultPartitionCheckInterval is how often PartitionManager polls the logs
+// table to decide whether a reorganization or retention drop is due.
+const defaultPartitionCheckInterval = time.Minute
+
+// logsOverflowPartition is the MAXVALUE catch-all partition everything lands
+// in once it outgrows the ranges created by initLogsPartitions.
+const logsOverflowPartition = "logsow"
+
+// PartitionManager is a background service that keeps the `logs` table's
+// range partitions ahead of chain growth: it watches MAX(id), reorganizes
+// logsow into a fresh bounded partition plus a new logsow once the active
+// partition is within headroom of its upper bound, and optionally drops the
+// oldest partition once retention is exceeded. Without it, operators running
+// months-long gateways have to hand-manage this in SQL.
+type PartitionManager struct {
+	db *gorm.DB
+
+	headroom  uint64
+	retention uint64
+
+	pollInterval time.Duration
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+
+	// metrics, surfaced for operators; updated on every check
+	currentPartitionIndex int
+	rowsInActivePartition uint64
+	lastReorgDuration     time.Duration
+}
+
+// NewPartitionManager creates a PartitionManager for db, reorganizing
+// headroom rows before the active partition's upper bound and retaining at
+// most retention partitions (0 disables retention-based dropping).
+func NewPartitionManager(db *gorm.DB, headroom, retention uint64) *PartitionManager {
+	return &PartitionManager{
+		db:           db,
+		headroom:     headroom,
+		retention:    retention,
+		pollInterval: defaultPartitionCheckInterval,
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+}
+
+// Start begins polling in a dedicated goroutine. It returns immediately;
+// call Stop to shut it down.
+func (pm *PartitionManager) Start() {
+	go pm.run()
+}
+
+// Stop signals the polling loop to exit and waits for it to finish.
+func (pm *PartitionManager) Stop() {
+	close(pm.stopCh)
+	<-pm.doneCh
+}
+
+func (pm *PartitionManager) run() {
+	defer close(pm.doneCh)
+
+	ticker := time.NewTicker(pm.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pm.stopCh:
+			return
+		case <-ticker.C:
+			if err := pm.checkOnce(); err != nil {
+				logrus.WithError(err).Error("Logs partition manager check failed")
+			}
+		}
+	}
+}
+
+// checkOnce inspects the logs table's current partitioning and reorganizes
+// or drops partitions as needed.
+func (pm *PartitionManager) checkOnce() error {
+	partitions, err := pm.loadPartitions()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load logs table partitions")
+	}
+
+	maxId, err := pm.maxLogId()
+	if err != nil {
+		return errors.WithMessage(err, "failed to query MAX(id) on logs")
+	}
+
+	// loadPartitions includes the trailing logsow catch-all, which isn't a
+	// bounded range partition - every count below excludes it.
+	boundedPartitions := len(partitions) - 1
+
+	pm.currentPartitionIndex = boundedPartitions - 1
+	pm.rowsInActivePartition = maxId % LogsTablePartitionRangeSize
+
+	activeUpperBound := uint64(boundedPartitions) * LogsTablePartitionRangeSize
+	if maxId+pm.headroom >= activeUpperBound {
+		if err := pm.reorganize(boundedPartitions); err != nil {
+			return errors.WithMessage(err, "failed to reorganize logs partitions")
+		}
+	}
+
+	if pm.retention > 0 && uint64(boundedPartitions) > pm.retention {
+		if err := pm.dropOldest(partitions[0]); err != nil {
+			return errors.WithMessage(err, "failed to drop oldest logs partition")
+		}
+	}
+
+	return nil
+}
+
+// reorganize splits logsow into a new bounded partition for the next range
+// plus a fresh logsow, e.g.
+//
+//	ALTER TABLE logs REORGANIZE PARTITION logsow INTO (
+//	  PARTITION logsN VALUES LESS THAN (...), PARTITION logsow VALUES LESS THAN MAXVALUE)
+func (pm *PartitionManager) reorganize(nextIndex int) error {
+	start := time.Now()
+
+	upperBound := uint64(nextIndex+1) * LogsTablePartitionRangeSize
+
+	sql := fmt.Sprintf(
+		"ALTER TABLE logs REORGANIZE PARTITION %v INTO "+
+			"(PARTITION logs%v VALUES LESS THAN (%v), PARTITION %v VALUES LESS THAN MAXVALUE)",
+		logsOverflowPartition, nextIndex, upperBound, logsOverflowPartition,
+	)
+
+	logrus.WithField("sql", sql).Info("Reorganizing logs table partitions")
+
+	if err := pm.db.Exec(sql).Error; err != nil {
+		return err
+	}
+
+	pm.lastReorgDuration = time.Since(start)
+	return nil
+}
+
+// dropOldest drops the oldest non-overflow partition once retention is
+// exceeded, archiving is left to operators (e.g. via binlog/snapshot) before
+// calling this.
+func (pm *PartitionManager) dropOldest(partition string) error {
+	logrus.WithField("partition", partition).Info("Dropping oldest logs table partition past retention")
+
+	return pm.db.Exec(fmt.Sprintf("ALTER TABLE logs DROP PARTITION %v", partition)).Error
+}
+
+// loadPartitions returns the logs table's partition names ordered from
+// oldest to newest, including the trailing logsow partition.
+func (pm *PartitionManager) loadPartitions() ([]string, error) {
+	rows, err := pm.db.Raw(
+		"SELECT PARTITION_NAME FROM information_schema.PARTITIONS " +
+			"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'logs' " +
+			"ORDER BY PARTITION_ORDINAL_POSITION ASC",
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+
+		partitions = append(partitions, name)
+	}
+
+	return partitions, nil
+}
+
+func (pm *PartitionManager) maxLogId() (uint64, error) {
+	var maxId sql.NullInt64
+
+	if err := pm.db.Table("logs").Select("MAX(id)").Find(&maxId).Error; err != nil {
+		return 0, err
+	}
+
+	if !maxId.Valid {
+		return 0, nil
+	}
+
+	return uint64(maxId.Int64), nil
+}
@@ -0,0 +1,104 @@
+package mysql
+
+import "gorm.io/gorm"
+
+const defaultBatchSizeBridgeMessageInsert = 1000
+
+// bridgeMessage records a single Scroll MessageQueue/L2ToL1MessagePasser event
+// indexed into the withdrawal Merkle tree, keyed by its leaf index.
+type bridgeMessage struct {
+	ID uint64
+
+	MessageHash string `gorm:"size:66;index:idx_message_hash,unique;not null"`
+	LeafIndex   uint64 `gorm:"index:idx_leaf_index,unique;not null"`
+
+	Sender    string `gorm:"size:66;not null"`
+	Recipient string `gorm:"size:66;not null"`
+
+	L2BlockNumber uint64 `gorm:"index;not null"`
+	Claimed       bool   `gorm:"not null;default:false"`
+}
+
+func (bridgeMessage) TableName() string {
+	return "bridge_messages"
+}
+
+// bridgeMessageStore tracks indexed withdrawal messages alongside their
+// position in the l1InfoTreeStore's Merkle tree.
+type bridgeMessageStore struct {
+	*baseStore
+}
+
+func newBridgeMessageStore(db *gorm.DB) *bridgeMessageStore {
+	return &bridgeMessageStore{
+		baseStore: newBaseStore(db),
+	}
+}
+
+// Pushn batch saves indexed bridge messages.
+func (s *bridgeMessageStore) Pushn(dbTx *gorm.DB, messages []*bridgeMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return dbTx.CreateInBatches(messages, defaultBatchSizeBridgeMessageInsert).Error
+}
+
+// ByHash returns the indexed message for the given message hash, if any.
+func (s *bridgeMessageStore) ByHash(hash string) (*bridgeMessage, bool, error) {
+	var msg bridgeMessage
+
+	existed, err := s.exists(&msg, "message_hash = ?", hash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &msg, existed, nil
+}
+
+// Pending returns unclaimed messages sent to recipient, paginated.
+func (s *bridgeMessageStore) Pending(recipient string, offset, limit int) ([]bridgeMessage, error) {
+	var messages []bridgeMessage
+
+	db := s.db.
+		Where("recipient = ? AND claimed = ?", recipient, false).
+		Order("leaf_index asc").
+		Offset(offset).
+		Limit(limit)
+
+	if err := db.Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// RollbackFrom deletes messages whose source block was orphaned, i.e. every
+// message with L2BlockNumber >= fromBlock.
+func (s *bridgeMessageStore) RollbackFrom(dbTx *gorm.DB, fromBlock uint64) error {
+	return dbTx.Where("l2_block_number >= ?", fromBlock).Delete(&bridgeMessage{}).Error
+}
+
+// MinLeafIndexFromBlock returns the smallest leaf index among messages
+// recorded at L2BlockNumber >= fromBlock, translating a reorg's orphaned
+// block-number boundary into the Merkle tree's leaf-index scale. found is
+// false when no messages were indexed at or after fromBlock, meaning there
+// is nothing for the tree to roll back.
+func (s *bridgeMessageStore) MinLeafIndexFromBlock(fromBlock uint64) (index uint64, found bool, err error) {
+	var messages []bridgeMessage
+
+	db := s.db.
+		Where("l2_block_number >= ?", fromBlock).
+		Order("leaf_index asc").
+		Limit(1)
+
+	if err := db.Find(&messages).Error; err != nil {
+		return 0, false, err
+	}
+
+	if len(messages) == 0 {
+		return 0, false, nil
+	}
+
+	return messages[0].LeafIndex, true, nil
+}
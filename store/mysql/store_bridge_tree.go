@@ -0,0 +1,345 @@
+package mysql
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultL1InfoTreeDepth is the depth of the sparse Merkle tree used to index
+// L2-to-L1 messages, matching the depth of the on-chain L1 info tree.
+const defaultL1InfoTreeDepth = 32
+
+// frontierCacheSize bounds the in-memory LRU of recently touched tree nodes,
+// covering a handful of pending subtrees without unbounded growth.
+const frontierCacheSize = 4096
+
+// l1InfoTreeNode is a single (level, index) -> hash node of the append-only
+// Merkle tree indexing Scroll MessageQueue/L2ToL1MessagePasser events. Level 0
+// holds message leaves; level `depth` holds the root.
+type l1InfoTreeNode struct {
+	ID    uint64
+	Level uint8  `gorm:"index:idx_level_index,unique;not null"`
+	Index uint64 `gorm:"index:idx_level_index,unique;not null"`
+	Hash  string `gorm:"size:66;not null"`
+}
+
+func (l1InfoTreeNode) TableName() string {
+	return "l1_info_tree_nodes"
+}
+
+// l1InfoTreeCheckpoint tracks the last L2 block (and its hash, to detect
+// reorgs) whose messages have been folded into the tree, so the indexer can
+// resume after a restart and roll back leaves orphaned by a reorg.
+type l1InfoTreeCheckpoint struct {
+	ID              uint64
+	LastL2Block     uint64 `gorm:"not null"`
+	LastL2BlockHash string `gorm:"size:66;not null;default:''"`
+	NextLeafIndex   uint64 `gorm:"not null"`
+}
+
+func (l1InfoTreeCheckpoint) TableName() string {
+	return "l1_info_tree_checkpoint"
+}
+
+// l1InfoTreeStore persists the append-only L1 info/withdrawal Merkle tree and
+// serves claim proofs against it.
+type l1InfoTreeStore struct {
+	*baseStore
+
+	depth uint8
+	// (level, index) => hash, for the most recently touched nodes that are
+	// known to have actually committed.
+	frontier *lru.Cache
+
+	// (level, index) => hash written by setNode within the current batch's
+	// dbTx, held back from frontier until that dbTx is known to have
+	// committed - see CommitPending/DiscardPending.
+	pendingMu sync.Mutex
+	pending   map[treeNodeKey][32]byte
+}
+
+func newL1InfoTreeStore(db *gorm.DB) *l1InfoTreeStore {
+	cache, _ := lru.New(frontierCacheSize)
+
+	return &l1InfoTreeStore{
+		baseStore: newBaseStore(db),
+		depth:     defaultL1InfoTreeDepth,
+		frontier:  cache,
+		pending:   make(map[treeNodeKey][32]byte),
+	}
+}
+
+type treeNodeKey struct {
+	level uint8
+	index uint64
+}
+
+// AppendLeaf inserts a new message leaf hash, recomputes every ancestor up to
+// the root within dbTx, and returns the leaf's index in the tree. checkpoint
+// is mutated in memory (NextLeafIndex advanced) but NOT persisted here -
+// callers folding in a whole batch of leaves call SaveCheckpoint once after
+// every AppendLeaf in the batch, so the leaf-index/block/hash advance
+// atomically together.
+func (s *l1InfoTreeStore) AppendLeaf(dbTx *gorm.DB, checkpoint *l1InfoTreeCheckpoint, leafHash [32]byte) (uint64, error) {
+	index := checkpoint.NextLeafIndex
+
+	if err := s.setNode(dbTx, 0, index, leafHash); err != nil {
+		return 0, errors.WithMessage(err, "failed to persist leaf node")
+	}
+
+	if err := s.recomputeAncestors(dbTx, index); err != nil {
+		return 0, errors.WithMessage(err, "failed to recompute ancestors")
+	}
+
+	checkpoint.NextLeafIndex++
+
+	return index, nil
+}
+
+// SaveCheckpoint persists checkpoint's current in-memory state (leaf index,
+// last processed block and hash) within dbTx.
+func (s *l1InfoTreeStore) SaveCheckpoint(dbTx *gorm.DB, checkpoint *l1InfoTreeCheckpoint) error {
+	return dbTx.Save(checkpoint).Error
+}
+
+// recomputeAncestors walks from a leaf up to the root, combining each node
+// with its sibling (an empty-subtree hash when the sibling does not exist yet).
+func (s *l1InfoTreeStore) recomputeAncestors(dbTx *gorm.DB, leafIndex uint64) error {
+	index := leafIndex
+
+	for level := uint8(0); level < s.depth; level++ {
+		nodeHash, err := s.getNodeInBatch(dbTx, level, index)
+		if err != nil {
+			return err
+		}
+
+		siblingIndex := index ^ 1
+		siblingHash, err := s.getNodeInBatch(dbTx, level, siblingIndex)
+		if err != nil {
+			return err
+		}
+
+		var left, right [32]byte
+		if index%2 == 0 {
+			left, right = nodeHash, siblingHash
+		} else {
+			left, right = siblingHash, nodeHash
+		}
+
+		parentIndex := index / 2
+		parentHash := combineHash(left, right)
+
+		if err := s.setNode(dbTx, level+1, parentIndex, parentHash); err != nil {
+			return err
+		}
+
+		index = parentIndex
+	}
+
+	return nil
+}
+
+// GetClaimProof returns the sibling hash at every level from leaf to root
+// (the Merkle path), along with the current root, for the leaf at index.
+func (s *l1InfoTreeStore) GetClaimProof(index uint64) (path [][32]byte, root [32]byte, err error) {
+	path = make([][32]byte, s.depth)
+
+	cur := index
+	for level := uint8(0); level < s.depth; level++ {
+		siblingHash, err := s.getNode(s.db, level, cur^1)
+		if err != nil {
+			return nil, root, err
+		}
+
+		path[level] = siblingHash
+		cur /= 2
+	}
+
+	root, err = s.getNode(s.db, s.depth, 0)
+	return path, root, err
+}
+
+// LatestRoot returns the current root hash of the tree.
+func (s *l1InfoTreeStore) LatestRoot() ([32]byte, error) {
+	return s.getNode(s.db, s.depth, 0)
+}
+
+// RollbackFrom deletes every leaf with index >= fromIndex and their ancestors,
+// recomputing the tree back to its state right before the orphaned block. It
+// is used when the L2 block that produced those messages gets reorged out.
+func (s *l1InfoTreeStore) RollbackFrom(dbTx *gorm.DB, fromIndex uint64) error {
+	if err := dbTx.Where("level = 0 AND `index` >= ?", fromIndex).Delete(&l1InfoTreeNode{}).Error; err != nil {
+		return errors.WithMessage(err, "failed to delete orphaned leaves")
+	}
+
+	s.frontier.Purge()
+
+	// recompute every remaining leaf's ancestors from scratch; simplest correct
+	// approach for a rare, non-hot-path reorg recovery operation.
+	var leaves []l1InfoTreeNode
+	if err := dbTx.Where("level = 0").Order("`index` asc").Find(&leaves).Error; err != nil {
+		return errors.WithMessage(err, "failed to reload remaining leaves")
+	}
+
+	if err := dbTx.Where("level > 0").Delete(&l1InfoTreeNode{}).Error; err != nil {
+		return errors.WithMessage(err, "failed to clear stale ancestor nodes")
+	}
+
+	for _, leaf := range leaves {
+		if err := s.recomputeAncestors(dbTx, leaf.Index); err != nil {
+			return err
+		}
+	}
+
+	return dbTx.Model(&l1InfoTreeCheckpoint{}).
+		Where("id = 1").
+		Update("next_leaf_index", fromIndex).Error
+}
+
+func (s *l1InfoTreeStore) loadOrInitCheckpoint(dbTx *gorm.DB) (*l1InfoTreeCheckpoint, error) {
+	var checkpoint l1InfoTreeCheckpoint
+
+	existed, err := s.exists(&checkpoint, "id = 1")
+	if err != nil {
+		return nil, err
+	}
+
+	if !existed {
+		checkpoint = l1InfoTreeCheckpoint{ID: 1}
+		if err := dbTx.Create(&checkpoint).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return &checkpoint, nil
+}
+
+func (s *l1InfoTreeStore) getNode(db *gorm.DB, level uint8, index uint64) ([32]byte, error) {
+	var empty [32]byte
+
+	if cached, ok := s.frontier.Get(treeNodeKey{level, index}); ok {
+		return cached.([32]byte), nil
+	}
+
+	var node l1InfoTreeNode
+
+	existed, err := s.exists(&node, "level = ? AND `index` = ?", level, index)
+	if err != nil {
+		return empty, err
+	}
+
+	if !existed {
+		return emptySubtreeHash(level), nil
+	}
+
+	hashBytes, err := hex.DecodeString(trimHexPrefix(node.Hash))
+	if err != nil {
+		return empty, err
+	}
+
+	var h [32]byte
+	copy(h[:], hashBytes)
+
+	s.frontier.Add(treeNodeKey{level, index}, h)
+	return h, nil
+}
+
+// getNodeInBatch is getNode plus a lookup against the current batch's pending
+// (written but not yet known to have committed) nodes. It must only be
+// called with the same dbTx those pending writes went through - e.g. from
+// recomputeAncestors, chained from the same AppendLeaf/RollbackFrom call
+// that produced them - never from a read path like GetClaimProof/LatestRoot
+// that uses s.db and could otherwise observe another goroutine's in-flight,
+// not-yet-committed batch.
+func (s *l1InfoTreeStore) getNodeInBatch(dbTx *gorm.DB, level uint8, index uint64) ([32]byte, error) {
+	s.pendingMu.Lock()
+	hash, ok := s.pending[treeNodeKey{level, index}]
+	s.pendingMu.Unlock()
+
+	if ok {
+		return hash, nil
+	}
+
+	return s.getNode(dbTx, level, index)
+}
+
+func (s *l1InfoTreeStore) setNode(dbTx *gorm.DB, level uint8, index uint64, hash [32]byte) error {
+	node := l1InfoTreeNode{
+		Level: level,
+		Index: index,
+		Hash:  "0x" + hex.EncodeToString(hash[:]),
+	}
+
+	onConflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "level"}, {Name: "index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hash"}),
+	}
+
+	if err := dbTx.Clauses(onConflict).Create(&node).Error; err != nil {
+		return err
+	}
+
+	// held back from frontier until the caller's dbTx is known to have
+	// committed - see CommitPending/DiscardPending.
+	s.pendingMu.Lock()
+	s.pending[treeNodeKey{level, index}] = hash
+	s.pendingMu.Unlock()
+
+	return nil
+}
+
+// CommitPending promotes every node setNode has written since the last
+// CommitPending/DiscardPending into the read-through frontier cache. Call
+// this only once the dbTx those writes went through has actually committed.
+func (s *l1InfoTreeStore) CommitPending() {
+	s.pendingMu.Lock()
+	pending := s.pending
+	s.pending = make(map[treeNodeKey][32]byte)
+	s.pendingMu.Unlock()
+
+	for key, hash := range pending {
+		s.frontier.Add(key, hash)
+	}
+}
+
+// DiscardPending drops every node setNode has written since the last
+// CommitPending/DiscardPending without caching them, for when the
+// surrounding dbTx rolled back and those writes never actually landed.
+func (s *l1InfoTreeStore) DiscardPending() {
+	s.pendingMu.Lock()
+	s.pending = make(map[treeNodeKey][32]byte)
+	s.pendingMu.Unlock()
+}
+
+// combineHash is the internal node hashing function: keccak256(left || right).
+func combineHash(left, right [32]byte) [32]byte {
+	var out [32]byte
+	copy(out[:], crypto.Keccak256(left[:], right[:]))
+	return out
+}
+
+// emptySubtreeHash returns the canonical hash of an empty subtree rooted at
+// level, used for siblings that have not been populated yet.
+func emptySubtreeHash(level uint8) [32]byte {
+	hash := [32]byte{} // level 0 empty leaf is the zero hash
+
+	for i := uint8(0); i < level; i++ {
+		hash = combineHash(hash, hash)
+	}
+
+	return hash
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+
+	return s
+}
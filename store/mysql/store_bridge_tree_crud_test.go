@@ -0,0 +1,218 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// newTestL1InfoTreeStore returns a store backed by a fresh in-memory sqlite
+// db, so AppendLeaf/RollbackFrom/GetClaimProof can be exercised without a
+// live mysql instance.
+func newTestL1InfoTreeStore(t *testing.T) *l1InfoTreeStore {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open in-memory sqlite db: %v", err)
+	}
+
+	if err := db.AutoMigrate(&l1InfoTreeNode{}, &l1InfoTreeCheckpoint{}); err != nil {
+		t.Fatalf("failed to migrate test schema: %v", err)
+	}
+
+	return newL1InfoTreeStore(db)
+}
+
+func leafHash(b byte) (h [32]byte) {
+	h[0] = b
+	return h
+}
+
+func TestAppendLeafAdvancesIndexAndRoot(t *testing.T) {
+	store := newTestL1InfoTreeStore(t)
+
+	checkpoint, err := store.loadOrInitCheckpoint(store.db)
+	if err != nil {
+		t.Fatalf("loadOrInitCheckpoint() error = %v", err)
+	}
+
+	index0, err := store.AppendLeaf(store.db, checkpoint, leafHash(0x01))
+	if err != nil {
+		t.Fatalf("AppendLeaf() error = %v", err)
+	}
+	if index0 != 0 {
+		t.Fatalf("first leaf index = %v, want 0", index0)
+	}
+
+	index1, err := store.AppendLeaf(store.db, checkpoint, leafHash(0x02))
+	if err != nil {
+		t.Fatalf("AppendLeaf() error = %v", err)
+	}
+	if index1 != 1 {
+		t.Fatalf("second leaf index = %v, want 1", index1)
+	}
+
+	if checkpoint.NextLeafIndex != 2 {
+		t.Fatalf("checkpoint.NextLeafIndex = %v, want 2", checkpoint.NextLeafIndex)
+	}
+
+	root, err := store.LatestRoot()
+	if err != nil {
+		t.Fatalf("LatestRoot() error = %v", err)
+	}
+	if root == ([32]byte{}) {
+		t.Fatalf("expected a non-zero root after appending leaves")
+	}
+}
+
+func TestGetClaimProofVerifiesAgainstRoot(t *testing.T) {
+	store := newTestL1InfoTreeStore(t)
+
+	checkpoint, err := store.loadOrInitCheckpoint(store.db)
+	if err != nil {
+		t.Fatalf("loadOrInitCheckpoint() error = %v", err)
+	}
+
+	for _, b := range []byte{0x01, 0x02, 0x03} {
+		if _, err := store.AppendLeaf(store.db, checkpoint, leafHash(b)); err != nil {
+			t.Fatalf("AppendLeaf() error = %v", err)
+		}
+	}
+
+	path, root, err := store.GetClaimProof(1)
+	if err != nil {
+		t.Fatalf("GetClaimProof() error = %v", err)
+	}
+
+	// recompute the root by walking the proof path up from the leaf at index 1
+	got := leafHash(0x02)
+	index := uint64(1)
+	for _, sibling := range path {
+		if index%2 == 0 {
+			got = combineHash(got, sibling)
+		} else {
+			got = combineHash(sibling, got)
+		}
+		index /= 2
+	}
+
+	if got != root {
+		t.Fatalf("recomputed root = %x, want %x", got, root)
+	}
+}
+
+func TestRollbackFromRemovesOrphanedLeavesAndRecomputesRoot(t *testing.T) {
+	store := newTestL1InfoTreeStore(t)
+
+	checkpoint, err := store.loadOrInitCheckpoint(store.db)
+	if err != nil {
+		t.Fatalf("loadOrInitCheckpoint() error = %v", err)
+	}
+
+	for _, b := range []byte{0x01, 0x02, 0x03} {
+		if _, err := store.AppendLeaf(store.db, checkpoint, leafHash(b)); err != nil {
+			t.Fatalf("AppendLeaf() error = %v", err)
+		}
+	}
+
+	rootBefore, err := store.LatestRoot()
+	if err != nil {
+		t.Fatalf("LatestRoot() error = %v", err)
+	}
+
+	if err := store.RollbackFrom(store.db, 1); err != nil {
+		t.Fatalf("RollbackFrom() error = %v", err)
+	}
+
+	reloaded, err := store.loadOrInitCheckpoint(store.db)
+	if err != nil {
+		t.Fatalf("loadOrInitCheckpoint() after rollback error = %v", err)
+	}
+	if reloaded.NextLeafIndex != 1 {
+		t.Fatalf("checkpoint.NextLeafIndex after rollback = %v, want 1", reloaded.NextLeafIndex)
+	}
+
+	// re-appending the leaf that was rolled back should reproduce the
+	// pre-rollback root, proving the tree state was genuinely reset rather
+	// than just the checkpoint counter.
+	if _, err := store.AppendLeaf(store.db, reloaded, leafHash(0x02)); err != nil {
+		t.Fatalf("AppendLeaf() after rollback error = %v", err)
+	}
+
+	rootAfter, err := store.LatestRoot()
+	if err != nil {
+		t.Fatalf("LatestRoot() error = %v", err)
+	}
+
+	if rootAfter != rootBefore {
+		t.Fatalf("root after rollback+replay = %x, want %x (pre-rollback root)", rootAfter, rootBefore)
+	}
+}
+
+// TestSetNodeDoesNotCacheFromARolledBackBatch proves the bug this gating
+// fixes: a node written by setNode inside a transaction that ultimately
+// rolls back must never reach the frontier cache, even though
+// recomputeAncestors read it back (via getNodeInBatch) to compute its parent
+// before the rollback happened.
+func TestSetNodeDoesNotCacheFromARolledBackBatch(t *testing.T) {
+	store := newTestL1InfoTreeStore(t)
+
+	checkpoint, err := store.loadOrInitCheckpoint(store.db)
+	if err != nil {
+		t.Fatalf("loadOrInitCheckpoint() error = %v", err)
+	}
+
+	err = store.db.Transaction(func(dbTx *gorm.DB) error {
+		if _, err := store.AppendLeaf(dbTx, checkpoint, leafHash(0x01)); err != nil {
+			return err
+		}
+
+		// simulate a later statement in the same batch failing, e.g. a
+		// unique constraint violation in a sibling store's Pushn.
+		return errors.New("simulated failure later in the batch")
+	})
+	if err == nil {
+		t.Fatalf("expected the transaction to fail")
+	}
+	store.DiscardPending()
+
+	if _, ok := store.frontier.Get(treeNodeKey{level: 0, index: 0}); ok {
+		t.Fatalf("expected frontier to not cache a node from a rolled-back transaction")
+	}
+
+	root, err := store.LatestRoot()
+	if err != nil {
+		t.Fatalf("LatestRoot() error = %v", err)
+	}
+	if root != ([32]byte{}) {
+		t.Fatalf("root after the only AppendLeaf rolled back = %x, want the empty root", root)
+	}
+}
+
+// TestCommitPendingCachesAfterASuccessfulBatch is the mirror image: once the
+// transaction actually commits, CommitPending must promote its writes into
+// the frontier so subsequent reads hit the cache.
+func TestCommitPendingCachesAfterASuccessfulBatch(t *testing.T) {
+	store := newTestL1InfoTreeStore(t)
+
+	checkpoint, err := store.loadOrInitCheckpoint(store.db)
+	if err != nil {
+		t.Fatalf("loadOrInitCheckpoint() error = %v", err)
+	}
+
+	err = store.db.Transaction(func(dbTx *gorm.DB) error {
+		_, err := store.AppendLeaf(dbTx, checkpoint, leafHash(0x01))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("transaction error = %v", err)
+	}
+	store.CommitPending()
+
+	if _, ok := store.frontier.Get(treeNodeKey{level: 0, index: 0}); !ok {
+		t.Fatalf("expected frontier to cache the leaf node once its transaction committed")
+	}
+}
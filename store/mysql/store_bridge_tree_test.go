@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCombineHashMatchesKeccak256Concat(t *testing.T) {
+	var left, right [32]byte
+	left[0] = 0x01
+	right[0] = 0x02
+
+	got := combineHash(left, right)
+	want := crypto.Keccak256(left[:], right[:])
+
+	if hex.EncodeToString(got[:]) != hex.EncodeToString(want) {
+		t.Fatalf("combineHash() = %x, want %x", got, want)
+	}
+}
+
+func TestEmptySubtreeHashIsDeterministicPerLevel(t *testing.T) {
+	// level 0 is always the zero leaf hash
+	if emptySubtreeHash(0) != ([32]byte{}) {
+		t.Fatalf("expected level 0 empty subtree hash to be the zero hash")
+	}
+
+	level1 := emptySubtreeHash(1)
+	wantLevel1 := combineHash([32]byte{}, [32]byte{})
+
+	if level1 != wantLevel1 {
+		t.Fatalf("emptySubtreeHash(1) = %x, want %x", level1, wantLevel1)
+	}
+
+	// each level combines the prior level's empty hash with itself
+	level2 := emptySubtreeHash(2)
+	wantLevel2 := combineHash(level1, level1)
+
+	if level2 != wantLevel2 {
+		t.Fatalf("emptySubtreeHash(2) = %x, want %x", level2, wantLevel2)
+	}
+}
+
+func TestTrimHexPrefix(t *testing.T) {
+	cases := map[string]string{
+		"0xabcd": "abcd",
+		"0XABCD": "ABCD",
+		"abcd":   "abcd",
+		"":       "",
+	}
+
+	for in, want := range cases {
+		if got := trimHexPrefix(in); got != want {
+			t.Fatalf("trimHexPrefix(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
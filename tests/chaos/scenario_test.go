@@ -0,0 +1,123 @@
+package chaos
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/scroll-tech/rpc-gateway/node"
+)
+
+// dispatch routes a request for key and, when a URL comes back, performs a
+// real HTTP round trip against it - the same wire-level traffic
+// clientProvider.getClient's RPC client factory would produce - so
+// FakeNode.Requests() reflects requests that genuinely landed, not just
+// what Route() claims it would send. clientProvider itself is unexported in
+// package node, so this is the closest this package (tests/chaos) can drive
+// the request without reaching into node's internals.
+func dispatch(router *node.ChaosRouter, group node.Group, key string) string {
+	url := router.Route(group, []byte(key))
+	if len(url) == 0 {
+		return ""
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		// a killed/unreachable node legitimately fails the round trip; that
+		// still counts as "no request landed" rather than a routed success.
+		return ""
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return url
+}
+
+// TestPartitionKillRestore partitions one node, kills another, restores both,
+// and verifies every request still lands on a node that can serve it - and
+// that the partitioned/killed nodes actually received zero of that traffic.
+func TestPartitionKillRestore(t *testing.T) {
+	cluster := NewCluster(3)
+	defer cluster.Close()
+
+	nodeA, nodeB, nodeC := cluster.Nodes[0], cluster.Nodes[1], cluster.Nodes[2]
+	group := node.Group("eth")
+
+	// partition node A: requests routed to it should observe no full node available
+	cluster.Router.Blackhole(nodeA.URL(), 200*time.Millisecond)
+
+	if url := dispatch(cluster.Router, group, "k1"); url == nodeA.URL() {
+		t.Fatalf("expected node A to be partitioned, but it was routed to")
+	}
+
+	// kill node B outright, independent of routing
+	nodeB.Close()
+	cluster.Router.Blackhole(nodeB.URL(), time.Hour)
+
+	for i := 0; i < len(cluster.Nodes); i++ {
+		url := dispatch(cluster.Router, group, "k")
+		if url == nodeA.URL() || url == nodeB.URL() {
+			t.Fatalf("expected request to avoid partitioned/killed node, got %v", url)
+		}
+	}
+
+	if got := nodeA.Requests(); got != 0 {
+		t.Fatalf("expected partitioned node A to have served 0 requests, got %v", got)
+	}
+	if got := nodeB.Requests(); got != 0 {
+		t.Fatalf("expected killed node B to have served 0 requests, got %v", got)
+	}
+	if nodeC.Requests() == 0 {
+		t.Fatalf("expected node C to have served the requests routed away from A and B - no request should be lost")
+	}
+
+	// restore node A and verify it becomes reachable again
+	time.Sleep(250 * time.Millisecond)
+
+	sawNodeA := false
+	for i := 0; i < len(cluster.Nodes)*4; i++ {
+		if dispatch(cluster.Router, group, "k") == nodeA.URL() {
+			sawNodeA = true
+			break
+		}
+	}
+
+	if !sawNodeA {
+		t.Fatalf("expected node A to be reachable again after blackhole window elapsed")
+	}
+
+	if nodeA.Requests() == 0 {
+		t.Fatalf("expected node A to have actually served a request once restored, not just been named by Route()")
+	}
+}
+
+// TestConfigDrivenFaultInjection verifies a fault scenario described up
+// front via ChaosFaultConfig takes effect immediately, without needing a
+// runtime call against the admin endpoint.
+func TestConfigDrivenFaultInjection(t *testing.T) {
+	nodeA := NewFakeNode("node-a")
+	nodeB := NewFakeNode("node-b")
+	defer nodeA.Close()
+	defer nodeB.Close()
+
+	router := node.NewChaosRouter(
+		&staticRouter{urls: []string{nodeA.URL(), nodeB.URL()}},
+		node.ChaosFaultConfig{URL: nodeA.URL(), BlackholeFor: time.Hour},
+	)
+
+	group := node.Group("eth")
+
+	for i := 0; i < 4; i++ {
+		if url := dispatch(router, group, "k"); url == nodeA.URL() {
+			t.Fatalf("expected node A to be blackholed from construction, but it was routed to")
+		}
+	}
+
+	if got := nodeA.Requests(); got != 0 {
+		t.Fatalf("expected config-blackholed node A to have served 0 requests, got %v", got)
+	}
+	if nodeB.Requests() == 0 {
+		t.Fatalf("expected node B to have served the requests routed away from the config-blackholed node A")
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/scroll-tech/rpc-gateway/node"
+	"github.com/sirupsen/logrus"
+)
+
+// chaosAdminRequest is the JSON body accepted by NewChaosAdminHandler, one
+// action per request.
+type chaosAdminRequest struct {
+	// Action is one of "latency", "blackhole", "force-route" or "reset".
+	Action string `json:"action"`
+
+	Url      string `json:"url,omitempty"`
+	Group    string `json:"group,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+// NewChaosAdminHandler returns an http.Handler that lets operators (or
+// integration tests) toggle fault injection on router at runtime, e.g.
+//
+//	POST /admin/chaos {"action":"blackhole","url":"http://node-a:8545","duration":"5s"}
+func NewChaosAdminHandler(router *node.ChaosRouter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req chaosAdminRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := applyChaosAction(router, req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logrus.WithField("request", req).Info("Applied chaos admin action")
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func applyChaosAction(router *node.ChaosRouter, req chaosAdminRequest) error {
+	switch req.Action {
+	case "reset":
+		router.Reset()
+		return nil
+	case "latency":
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return err
+		}
+		router.InjectLatency(req.Url, d)
+		return nil
+	case "blackhole":
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			return err
+		}
+		router.Blackhole(req.Url, d)
+		return nil
+	case "force-route":
+		router.ForceRoute(node.Group(req.Group), req.Url)
+		return nil
+	default:
+		return fmt.Errorf("unknown chaos action %q", req.Action)
+	}
+}